@@ -0,0 +1,191 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"go.uber.org/zap"
+
+	"subscription-service/auth"
+	"subscription-service/config"
+	"subscription-service/db"
+	"subscription-service/events"
+	"subscription-service/handlers"
+	"subscription-service/logger"
+	"subscription-service/repository"
+	"subscription-service/repository/memory"
+	"subscription-service/repository/postgres"
+	"subscription-service/repository/sqlite"
+	"subscription-service/websub"
+)
+
+// App is the wired-up set of dependencies the service runs with: config,
+// logger, DB connection, repository, event bus, handlers and the HTTP
+// router. It replaces the package-level logger.Log/db.DB globals so every
+// collaborator is constructed explicitly and can be swapped in tests.
+type App struct {
+	Config      *config.Config
+	Logger      *zap.SugaredLogger
+	DB          *sql.DB
+	Repo        repository.SubscriptionRepository
+	Users       repository.UserRepository
+	Blacklist   repository.TokenBlacklistRepository
+	Issuer      *auth.Issuer
+	Events      *events.Bus
+	EventRouter *message.Router
+	closeEvents func() error
+	Hub         *events.Hub
+	WebSub      *websub.Notifier
+	Handler     *handlers.SubscriptionHandler
+	Router      http.Handler
+}
+
+// New builds the application container: logger, DB connection, repository,
+// event bus and handlers, each constructed from the one before it.
+func New(cfg *config.Config) (*App, error) {
+	log, err := logger.New(logger.Config{
+		Development: cfg.Development,
+		Level:       cfg.Logging.Level,
+		Format:      cfg.Logging.Format,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not build logger: %v", err)
+	}
+
+	var conn *sql.DB
+	var repo repository.SubscriptionRepository
+	var users repository.UserRepository
+	var blacklist repository.TokenBlacklistRepository
+	var importJobs repository.ImportJobRepository
+	var webSubRepo repository.WebSubRepository
+
+	if cfg.DB.Dialect == "memory" {
+		repo = memory.NewSubscriptionRepository()
+		users = memory.NewUserRepository()
+		blacklist = memory.NewTokenBlacklistRepository()
+		importJobs = memory.NewImportJobRepository()
+		webSubRepo = memory.NewWebSubRepository()
+	} else {
+		conn, err = db.Connect(cfg.DB)
+		if err != nil {
+			return nil, fmt.Errorf("could not connect to database: %v", err)
+		}
+
+		if cfg.DB.Dialect == "sqlite" {
+			repo = sqlite.NewSubscriptionRepository(conn)
+			users = sqlite.NewUserRepository(conn)
+			blacklist = sqlite.NewTokenBlacklistRepository(conn)
+			importJobs = sqlite.NewImportJobRepository(conn)
+			webSubRepo = sqlite.NewWebSubRepository(conn)
+		} else {
+			repo = postgres.NewSubscriptionRepository(conn)
+			users = postgres.NewUserRepository(conn)
+			blacklist = postgres.NewTokenBlacklistRepository(conn)
+			importJobs = postgres.NewImportJobRepository(conn)
+			webSubRepo = postgres.NewWebSubRepository(conn)
+		}
+	}
+
+	issuer := auth.NewIssuer(cfg.JWT.Secret, cfg.JWT.AccessTTL, cfg.JWT.RefreshTTL)
+
+	hub := events.NewHub(log)
+	bus, eventRouter, closeEvents, err := buildEventBus(cfg.MQ, conn, repo, hub, log)
+	if err != nil {
+		return nil, fmt.Errorf("could not build event bus: %v", err)
+	}
+
+	notifier := websub.NewNotifier(webSubRepo, log)
+	handler := handlers.NewSubscriptionHandler(repo, bus, importJobs, hub, webSubRepo)
+	authHandler := handlers.NewAuthHandler(users, blacklist, issuer)
+
+	return &App{
+		Config:      cfg,
+		Logger:      log,
+		DB:          conn,
+		Repo:        repo,
+		Users:       users,
+		Blacklist:   blacklist,
+		Issuer:      issuer,
+		Events:      bus,
+		EventRouter: eventRouter,
+		closeEvents: closeEvents,
+		Hub:         hub,
+		WebSub:      notifier,
+		Handler:     handler,
+		Router:      newRouter(handler, authHandler, issuer, blacklist, log),
+	}, nil
+}
+
+// Run applies migrations, seeds and serves HTTP until ctx is cancelled, then
+// shuts the server down gracefully.
+func (a *App) Run(ctx context.Context) error {
+	defer a.Logger.Sync()
+
+	if a.DB != nil {
+		defer a.DB.Close()
+
+		fresh, err := db.IsFreshDatabase(a.DB, a.Config.DB.Dialect)
+		if err != nil {
+			return fmt.Errorf("could not determine schema state: %v", err)
+		}
+
+		a.Logger.Info("Running database migrations...")
+		if err := db.MigrateUp(a.DB, a.Config.DB.Dialect, a.Logger); err != nil {
+			return fmt.Errorf("migration failed: %v", err)
+		}
+
+		if err := db.EnsureSchemaAtHead(a.DB, a.Config.DB.Dialect); err != nil {
+			return fmt.Errorf("schema is not at head: %v", err)
+		}
+
+		a.Logger.Info("Running database seeds...")
+		if err := db.RunSeeds(a.DB, fresh, a.Logger); err != nil {
+			a.Logger.Warnf("Seeds warning: %v", err)
+		}
+	} else {
+		a.Logger.Info("DB_DIALECT=memory: skipping migrations and seeds")
+	}
+
+	go runEventRouter(ctx, a.EventRouter, a.Logger)
+	defer a.EventRouter.Close()
+	go a.WebSub.Run(ctx, a.Hub)
+	defer func() {
+		if err := a.Events.Close(); err != nil {
+			a.Logger.Warnw("Error closing event bus publisher", "error", err)
+		}
+		if err := a.closeEvents(); err != nil {
+			a.Logger.Warnw("Error closing dead-letter publisher", "error", err)
+		}
+	}()
+
+	srv := &http.Server{
+		Addr:    ":" + a.Config.AppPort,
+		Handler: a.Router,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		a.Logger.Infow("Server is starting", "port", a.Config.AppPort)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			serveErr <- err
+			return
+		}
+		serveErr <- nil
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	a.Logger.Info("Shutting down server...")
+	return srv.Shutdown(shutdownCtx)
+}
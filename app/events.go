@@ -0,0 +1,109 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"go.uber.org/zap"
+
+	"subscription-service/config"
+	"subscription-service/events"
+)
+
+// buildEventBus wires the events.Bus used by SubscriptionHandler (sql outbox
+// or broker publisher, per cfg.MQ.Dialect), the router that consumes the
+// subscription lifecycle topics on the other end, and the in-process hub
+// that fans those same events out to SSE subscribers. The returned close
+// func releases the dead-letter publisher, which neither the bus nor the
+// router otherwise owns.
+//
+// conn is nil only for DB_DIALECT=memory, which has no database for the sql
+// dialect's outbox or watermill-sql subscriber to use regardless of
+// MQ_DIALECT: that case runs the whole pipeline on an in-process pub/sub
+// instead of the configured driver.
+func buildEventBus(cfg config.MQ, conn *sql.DB, repo events.TxBeginner, hub *events.Hub, log *zap.SugaredLogger) (*events.Bus, *message.Router, func() error, error) {
+	wmLog := events.NewWatermillLogger(log)
+	driverCfg := events.Config{
+		Dialect:         events.Dialect(cfg.Dialect),
+		AMQPURL:         cfg.AMQPURL,
+		KafkaBrokers:    cfg.KafkaBrokers,
+		DeadLetterTopic: events.Topic(cfg.DeadLetterTopic),
+	}
+
+	var bus *events.Bus
+	var subscriber message.Subscriber
+	var deadLetterPub message.Publisher
+
+	if conn == nil {
+		log.Infow("DB_DIALECT=memory: running the event bus on an in-process pub/sub", "configured_mq_dialect", cfg.Dialect)
+		memPubSub := events.NewMemoryPubSub(wmLog)
+		bus = events.NewBrokerBus(repo, events.NewWatermillPublisher(memPubSub), log)
+		subscriber = memPubSub
+		deadLetterPub = memPubSub
+	} else {
+		if driverCfg.Dialect == events.DialectSQL {
+			bus = events.NewSQLBus(repo, log)
+		} else {
+			pub, err := events.NewBrokerPublisher(driverCfg, wmLog)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			bus = events.NewBrokerBus(repo, pub, log)
+		}
+
+		var err error
+		subscriber, err = events.NewSubscriber(driverCfg, conn, wmLog)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		deadLetterPub, err = events.NewRawPublisher(driverCfg, conn, wmLog)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	router, err := events.NewRouter(wmLog, deadLetterPub, driverCfg.DeadLetterTopic)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	for _, topic := range []events.Topic{
+		events.TopicSubscriptionCreated,
+		events.TopicSubscriptionUpdated,
+		events.TopicSubscriptionCancelled,
+		events.TopicSubscriptionRenewed,
+		events.TopicSubscriptionRestored,
+	} {
+		topic := topic
+		router.AddNoPublisherHandler(
+			"log-"+string(topic),
+			string(topic),
+			subscriber,
+			func(msg *message.Message) error {
+				log.Infow("Subscription event received", "topic", topic, "message_uuid", msg.UUID, "payload", string(msg.Payload))
+
+				var event events.SubscriptionEvent
+				if err := json.Unmarshal(msg.Payload, &event); err != nil {
+					log.Errorw("Could not decode event for SSE hub", "error", err, "topic", topic, "message_uuid", msg.UUID)
+					return nil
+				}
+				hub.Publish(event)
+
+				return nil
+			},
+		)
+	}
+
+	return bus, router, deadLetterPub.Close, nil
+}
+
+// runEventRouter runs router until ctx is cancelled, logging (rather than
+// failing app startup) if the bus itself stops unexpectedly.
+func runEventRouter(ctx context.Context, router *message.Router, log *zap.SugaredLogger) {
+	if err := router.Run(ctx); err != nil {
+		log.Errorw("Event router stopped", "error", err)
+	}
+}
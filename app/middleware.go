@@ -0,0 +1,143 @@
+package app
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"subscription-service/auth"
+	"subscription-service/logger"
+	"subscription-service/query"
+	"subscription-service/repository"
+)
+
+// requestLogger accepts or generates an X-Request-ID, attaches it to the
+// response, and injects a child logger carrying request_id/method/path/user_id
+// into the request context so handlers can log via logger.FromContext instead
+// of a shared logger. It also logs the request once it completes, replacing
+// gin's own request logging middleware.
+func requestLogger(log *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader("X-Request-ID")
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+		c.Header("X-Request-ID", requestID)
+
+		reqLog := log.With(
+			"request_id", requestID,
+			"method", c.Request.Method,
+			"path", c.FullPath(),
+			"user_id", c.Param("user_id"),
+		)
+		c.Request = c.Request.WithContext(logger.WithContext(c.Request.Context(), reqLog))
+
+		start := time.Now()
+		c.Next()
+
+		reqLog.Infow("HTTP request",
+			"status", c.Writer.Status(),
+			"latency", time.Since(start),
+			"client_ip", c.ClientIP(),
+		)
+	}
+}
+
+// AuthRequired parses the `Bearer <token>` Authorization header, rejects
+// anything expired, mis-signed or logged-out (checked against blacklist),
+// and attaches the resulting claims to the request context via
+// auth.WithClaims for downstream middlewares and handlers.
+func AuthRequired(issuer *auth.Issuer, blacklist repository.TokenBlacklistRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		tokenString, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || tokenString == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing or invalid authorization header"})
+			return
+		}
+
+		claims, err := issuer.ParseAccessToken(tokenString)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+			return
+		}
+
+		revoked, err := blacklist.IsRevoked(c.Request.Context(), claims.ID)
+		if err != nil {
+			logger.FromContext(c.Request.Context()).Errorw("Failed to check token blacklist", "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+		if revoked {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token has been revoked"})
+			return
+		}
+
+		c.Request = c.Request.WithContext(auth.WithClaims(c.Request.Context(), claims))
+		c.Next()
+	}
+}
+
+// AdminRequired rejects the request with 403 unless AuthRequired has already
+// attached claims with is_admin set.
+func AdminRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := auth.ClaimsFromContext(c.Request.Context())
+		if !ok || !claims.IsAdmin {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// requireSelfOrAdmin rejects routes addressing a path :user_id other than
+// the caller's own, unless the caller is admin.
+func requireSelfOrAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := auth.ClaimsFromContext(c.Request.Context())
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		if !claims.IsAdmin && c.Param("user_id") != claims.UserID {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// requireFilteredOrAdmin rejects ListSubscriptions/GetTotalCost requests
+// that would otherwise return every user's data: a non-admin caller must
+// scope their `q` filter to their own user_id with a top-level equality
+// term. Handlers re-parse q themselves; a malformed expression is reported
+// as a 400 there, not here.
+func requireFilteredOrAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := auth.ClaimsFromContext(c.Request.Context())
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		if claims.IsAdmin {
+			c.Next()
+			return
+		}
+		filter, err := query.Parse(c.Query("q"))
+		if err != nil {
+			c.Next()
+			return
+		}
+		userID, ok := query.RequiredUserID(filter)
+		if !ok || userID != claims.UserID {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "must filter by your own user_id, or be admin"})
+			return
+		}
+		c.Next()
+	}
+}
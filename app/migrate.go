@@ -0,0 +1,72 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+
+	"subscription-service/config"
+	"subscription-service/db"
+	"subscription-service/logger"
+)
+
+// RunMigrateCommand handles the `migrate up|down N|force V|version` CLI
+// surface: it opens a DB connection and a logger without building the rest
+// of the container or starting the HTTP server.
+func RunMigrateCommand(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: migrate <up|down N|force V|version>")
+	}
+
+	if cfg.DB.Dialect == "memory" {
+		return fmt.Errorf("DB_DIALECT=memory has no schema to migrate")
+	}
+
+	log, err := logger.New(logger.Config{
+		Development: cfg.Development,
+		Level:       cfg.Logging.Level,
+		Format:      cfg.Logging.Format,
+	})
+	if err != nil {
+		return fmt.Errorf("could not build logger: %v", err)
+	}
+	defer log.Sync()
+
+	conn, err := db.Connect(cfg.DB)
+	if err != nil {
+		return fmt.Errorf("could not connect to database: %v", err)
+	}
+	defer conn.Close()
+
+	switch args[0] {
+	case "up":
+		return db.MigrateUp(conn, cfg.DB.Dialect, log)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid step count %q: %v", args[1], err)
+			}
+			steps = n
+		}
+		return db.MigrateDown(conn, cfg.DB.Dialect, steps, log)
+	case "force":
+		if len(args) < 2 {
+			return fmt.Errorf("migrate force requires a version argument")
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid version %q: %v", args[1], err)
+		}
+		return db.MigrateForce(conn, cfg.DB.Dialect, version, log)
+	case "version":
+		version, dirty, err := db.MigrateVersion(conn, cfg.DB.Dialect)
+		if err != nil {
+			return fmt.Errorf("migrate version failed: %v", err)
+		}
+		fmt.Printf("version=%d dirty=%t\n", version, dirty)
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate subcommand %q", args[0])
+	}
+}
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.uber.org/zap"
+
+	"subscription-service/auth"
+	_ "subscription-service/docs" // ОЧЕНЬ ВАЖНО: добавьте этот импорт! Путь должен совпадать с вашим модулем.
+	"subscription-service/handlers"
+	"subscription-service/repository"
+)
+
+// newRouter wires the gin engine: swagger, request logging, recovery, the
+// signup/login/refresh/logout auth routes and the CRUDL + analytics routes,
+// the latter behind AuthRequired and self-or-admin authorization.
+func newRouter(handler *handlers.SubscriptionHandler, authHandler *handlers.AuthHandler, issuer *auth.Issuer, blacklist repository.TokenBlacklistRepository, log *zap.SugaredLogger) *gin.Engine {
+	router := gin.New()
+
+	url := ginSwagger.URL("/swagger/doc.json")
+	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, url))
+
+	router.Use(requestLogger(log))
+	router.Use(gin.Recovery())
+
+	// Маршруты аутентификации
+	router.POST("/auth/signup", authHandler.Signup)
+	router.POST("/auth/login", authHandler.Login)
+	router.POST("/auth/refresh", authHandler.Refresh)
+	router.POST("/auth/logout", AuthRequired(issuer, blacklist), authHandler.Logout)
+
+	// Маршруты для CRUDL операций: требуют аутентификации, и доступ
+	// ограничен владельцем user_id или администратором
+	subscriptions := router.Group("/subscriptions")
+	subscriptions.Use(AuthRequired(issuer, blacklist))
+	subscriptions.POST("/:user_id/:service_name", requireSelfOrAdmin(), handler.CreateSubscription)
+	subscriptions.GET("/:user_id/:service_name", requireSelfOrAdmin(), handler.GetSubscription)
+	subscriptions.PUT("/:user_id/:service_name", requireSelfOrAdmin(), handler.UpdateSubscription)
+	subscriptions.DELETE("/:user_id/:service_name", requireSelfOrAdmin(), handler.DeleteSubscription)
+	subscriptions.POST("/:user_id/:service_name/restore", requireSelfOrAdmin(), handler.RestoreSubscription)
+	subscriptions.GET("/:user_id/:service_name/history", requireSelfOrAdmin(), handler.GetSubscriptionHistory)
+	subscriptions.GET("", requireFilteredOrAdmin(), handler.ListSubscriptions)
+	subscriptions.GET("/total", requireFilteredOrAdmin(), handler.GetTotalCost)
+	subscriptions.GET("/timeseries", requireFilteredOrAdmin(), handler.GetTimeseries)
+
+	// StreamEvents enforces its own user_id filter check (the filter lives in
+	// a query clause, not a query param requireFilteredOrAdmin can read).
+	subscriptions.GET("/stream", handler.StreamEvents)
+
+	// Bulk import spans whatever users the uploaded file mentions, so it's
+	// admin-only rather than self-or-admin like the single-subscription routes.
+	subscriptions.POST("/import", AdminRequired(), handler.ImportSubscriptions)
+	subscriptions.GET("/import/:job_id", AdminRequired(), handler.GetImportJob)
+
+	// WebSub hubs are conventionally open: hub.callback ownership is proven
+	// by the challenge-echo verification itself, not a bearer token.
+	router.POST("/websub", handler.Websub)
+
+	return router
+}
@@ -0,0 +1,24 @@
+package auth
+
+import "github.com/golang-jwt/jwt/v5"
+
+// TokenType distinguishes an access token from a refresh token in the Typ
+// claim, so a token issued for one purpose can't be accepted for the other.
+type TokenType string
+
+const (
+	TokenTypeAccess  TokenType = "access"
+	TokenTypeRefresh TokenType = "refresh"
+)
+
+// Claims is the JWT payload issued on login/refresh: the standard registered
+// claims (jti, iat, exp) plus the subject's user_id and admin flag, so
+// AuthRequired can authorize a request without a database round-trip. Typ
+// records which of the pair this token is, so an access token can't be used
+// at /auth/refresh and a refresh token can't authorize a request.
+type Claims struct {
+	UserID  string    `json:"user_id"`
+	IsAdmin bool      `json:"is_admin"`
+	Typ     TokenType `json:"typ"`
+	jwt.RegisteredClaims
+}
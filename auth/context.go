@@ -0,0 +1,19 @@
+package auth
+
+import "context"
+
+type ctxKey struct{}
+
+// WithClaims returns a copy of ctx carrying claims, retrievable with
+// ClaimsFromContext. AuthRequired attaches the claims of the bearer token it
+// just validated so downstream handlers and middlewares don't re-parse it.
+func WithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, ctxKey{}, claims)
+}
+
+// ClaimsFromContext returns the claims attached to ctx by WithClaims, and
+// whether any were found.
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(ctxKey{}).(*Claims)
+	return claims, ok
+}
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidToken is returned for any malformed, expired or mis-signed token,
+// without distinguishing the cause to the caller.
+var ErrInvalidToken = errors.New("invalid token")
+
+// Issuer signs and verifies the HS256 access/refresh token pair with
+// JWT_SECRET. Access tokens authorize requests; refresh tokens only exchange
+// for a new pair via /auth/refresh.
+type Issuer struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+func NewIssuer(secret string, accessTTL, refreshTTL time.Duration) *Issuer {
+	return &Issuer{secret: []byte(secret), accessTTL: accessTTL, refreshTTL: refreshTTL}
+}
+
+// IssueAccessToken signs a short-lived token carrying userID/isAdmin.
+func (i *Issuer) IssueAccessToken(userID string, isAdmin bool) (string, error) {
+	return i.sign(userID, isAdmin, TokenTypeAccess, i.accessTTL)
+}
+
+// IssueRefreshToken signs a longer-lived token used only to obtain a new pair.
+func (i *Issuer) IssueRefreshToken(userID string, isAdmin bool) (string, error) {
+	return i.sign(userID, isAdmin, TokenTypeRefresh, i.refreshTTL)
+}
+
+func (i *Issuer) sign(userID string, isAdmin bool, typ TokenType, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		UserID:  userID,
+		IsAdmin: isAdmin,
+		Typ:     typ,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(i.secret)
+}
+
+// Parse validates tokenString's signature and expiry and returns its claims,
+// without checking Typ. Callers that need one specific token type should use
+// ParseAccessToken or ParseRefreshToken instead.
+func (i *Issuer) Parse(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrInvalidToken
+		}
+		return i.secret, nil
+	})
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// ParseAccessToken parses tokenString and rejects it unless it is an access
+// token, so a refresh token can't be used as a bearer credential.
+func (i *Issuer) ParseAccessToken(tokenString string) (*Claims, error) {
+	return i.parseTyped(tokenString, TokenTypeAccess)
+}
+
+// ParseRefreshToken parses tokenString and rejects it unless it is a refresh
+// token, so an access token can't be exchanged at /auth/refresh.
+func (i *Issuer) ParseRefreshToken(tokenString string) (*Claims, error) {
+	return i.parseTyped(tokenString, TokenTypeRefresh)
+}
+
+func (i *Issuer) parseTyped(tokenString string, want TokenType) (*Claims, error) {
+	claims, err := i.Parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Typ != want {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
@@ -6,114 +6,100 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
-	"subscription-service/logger"
 	"time"
 
-	"github.com/joho/godotenv"
+	"go.uber.org/zap"
+
+	"subscription-service/config"
+
 	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
 )
 
-var DB *sql.DB
-
-func InitDB() {
-	// Загружаем переменные из .env файла
-	err := godotenv.Load()
-	if err != nil {
-		logger.Log.Info("No .env file found. Using system environment variables")
+// Connect opens the pool of connections selected by cfg.Dialect (DB_DIALECT):
+// "postgres" (the default) or "sqlite". The caller (app.New) owns the
+// returned connection and passes it on explicitly rather than through a
+// package-level variable.
+func Connect(cfg config.DB) (*sql.DB, error) {
+	switch cfg.Dialect {
+	case "sqlite":
+		return connectSQLite(cfg)
+	default:
+		return connectPostgres(cfg)
 	}
+}
 
-	// Читаем конфигурацию из переменных окружения
-	host := getEnv("DB_HOST", "localhost")
-	portStr := getEnv("DB_PORT", "5432")
-	user := getEnv("DB_USER", "postgres")
-	password := getEnv("DB_PASSWORD", "")
-	dbname := getEnv("DB_NAME", "ForTZ")
-
-	port, err := strconv.Atoi(portStr)
+func connectPostgres(cfg config.DB) (*sql.DB, error) {
+	port, err := strconv.Atoi(cfg.Port)
 	if err != nil {
-		logger.Log.Fatal("Invalid DB_PORT: ", err)
+		return nil, fmt.Errorf("invalid DB_PORT: %v", err)
 	}
 
 	psqlInfo := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
-		host, port, user, password, dbname)
-
-	logger.Log.Infow("Connecting to database", "host", host, "port", port, "dbname", dbname)
+		cfg.Host, port, cfg.User, cfg.Password, cfg.Name)
 
-	DB, err = sql.Open("postgres", psqlInfo)
+	conn, err := sql.Open("postgres", psqlInfo)
 	if err != nil {
-		logger.Log.Fatal("Failed to open database connection: ", err)
+		return nil, fmt.Errorf("failed to open database connection: %v", err)
 	}
 
 	// Устанавливаем максимальное количество открытых соединений
-	DB.SetMaxOpenConns(25)
-	DB.SetMaxIdleConns(25)
-	DB.SetConnMaxLifetime(5 * time.Minute)
+	conn.SetMaxOpenConns(25)
+	conn.SetMaxIdleConns(25)
+	conn.SetConnMaxLifetime(5 * time.Minute)
 
-	err = DB.Ping()
-	if err != nil {
-		logger.Log.Fatal("Failed to ping database: ", err)
+	if err := conn.Ping(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to ping database: %v", err)
 	}
 
-	logger.Log.Info("Database connection established successfully")
+	return conn, nil
 }
 
-// Вспомогательная функция для получения переменных окружения
-func getEnv(key, defaultValue string) string {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
+// connectSQLite opens the SQLite file at cfg.SQLitePath. SQLite only
+// supports a single writer at a time, so the pool is capped at one
+// connection to avoid spurious SQLITE_BUSY errors under concurrent requests.
+func connectSQLite(cfg config.DB) (*sql.DB, error) {
+	conn, err := sql.Open("sqlite3", cfg.SQLitePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database connection: %v", err)
 	}
-	return value
-}
 
-func RunMigrations(db *sql.DB) error {
-	migrationPath := filepath.Join("db", "migrations", "001_init.up.sql")
-	logger.Log.Infow("Applying migration", "file", migrationPath)
+	conn.SetMaxOpenConns(1)
 
-	sqlBytes, err := os.ReadFile(migrationPath)
-	if err != nil {
-		logger.Log.Errorw("Could not read migration file", "error", err, "path", migrationPath)
-		return fmt.Errorf("could not read migration file: %v", err)
+	if err := conn.Ping(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to ping database: %v", err)
 	}
 
-	_, err = db.Exec(string(sqlBytes))
-	if err != nil {
-		logger.Log.Errorw("Migration execution failed", "error", err)
-		return fmt.Errorf("migration failed: %v", err)
+	return conn, nil
+}
+
+// RunSeeds засеивает начальные данные, но только если миграции применялись
+// к этой базе впервые в рамках текущего запуска (fresh == true). Раньше это
+// решалось через SELECT COUNT(*), что сеяло данные заново после ручного
+// удаления всех строк; теперь решение привязано к состоянию миграций.
+func RunSeeds(db *sql.DB, fresh bool, log *zap.SugaredLogger) error {
+	if !fresh {
+		log.Info("Schema was already at head, skipping seeds")
+		return nil
 	}
 
-	logger.Log.Info("Migration applied successfully!")
-	return nil
-}
+	seedsPath := filepath.Join("db", "seeds", "001_initial_data.sql")
+	log.Infow("Applying seeds", "file", seedsPath)
 
-func RunSeeds(db *sql.DB) error {
-	var count int
-	err := db.QueryRow("SELECT COUNT(*) FROM subscriptions").Scan(&count)
+	sqlBytes, err := os.ReadFile(seedsPath)
 	if err != nil {
-		logger.Log.Errorw("Could not check data count", "error", err)
-		return fmt.Errorf("could not check data count: %v", err)
+		log.Errorw("Could not read seeds file", "error", err, "path", seedsPath)
+		return fmt.Errorf("could not read seeds file: %v", err)
 	}
 
-	if count == 0 {
-		seedsPath := filepath.Join("db", "seeds", "001_initial_data.sql")
-		logger.Log.Infow("Applying seeds", "file", seedsPath)
-
-		sqlBytes, err := os.ReadFile(seedsPath)
-		if err != nil {
-			logger.Log.Errorw("Could not read seeds file", "error", err, "path", seedsPath)
-			return fmt.Errorf("could not read seeds file: %v", err)
-		}
-
-		_, err = db.Exec(string(sqlBytes))
-		if err != nil {
-			logger.Log.Errorw("Seeds execution failed", "error", err)
-			return fmt.Errorf("seeds failed: %v", err)
-		}
-
-		logger.Log.Infof("Seeds applied successfully! Added %d records", count)
-	} else {
-		logger.Log.Infof("Table already contains %d records, skipping seeds", count)
+	_, err = db.Exec(string(sqlBytes))
+	if err != nil {
+		log.Errorw("Seeds execution failed", "error", err)
+		return fmt.Errorf("seeds failed: %v", err)
 	}
 
+	log.Info("Seeds applied successfully!")
 	return nil
 }
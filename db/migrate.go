@@ -0,0 +1,175 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/database/sqlite3"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+
+	"go.uber.org/zap"
+)
+
+const migrationsRoot = "db/migrations"
+
+var migrationFileRe = regexp.MustCompile(`^(\d+)_.*\.up\.sql$`)
+
+// migrationsDir returns the dialect-specific migrations directory: each
+// dialect keeps its own up/down pairs, since the schema (column types,
+// constraint syntax) differs between Postgres and SQLite.
+func migrationsDir(dialect string) string {
+	return filepath.Join(migrationsRoot, dialect)
+}
+
+// newMigrator строит golang-migrate поверх уже открытого соединения,
+// используя каталог db/migrations/<dialect> как источник up/down пар.
+func newMigrator(db *sql.DB, dialect string) (*migrate.Migrate, error) {
+	dir := migrationsDir(dialect)
+
+	switch dialect {
+	case "sqlite":
+		driver, err := sqlite3.WithInstance(db, &sqlite3.Config{})
+		if err != nil {
+			return nil, fmt.Errorf("could not create sqlite driver: %v", err)
+		}
+		m, err := migrate.NewWithDatabaseInstance("file://"+dir, "sqlite3", driver)
+		if err != nil {
+			return nil, fmt.Errorf("could not initialize migrator: %v", err)
+		}
+		return m, nil
+	default:
+		driver, err := postgres.WithInstance(db, &postgres.Config{})
+		if err != nil {
+			return nil, fmt.Errorf("could not create postgres driver: %v", err)
+		}
+		m, err := migrate.NewWithDatabaseInstance("file://"+dir, "postgres", driver)
+		if err != nil {
+			return nil, fmt.Errorf("could not initialize migrator: %v", err)
+		}
+		return m, nil
+	}
+}
+
+// MigrateUp применяет все ещё не применённые миграции.
+func MigrateUp(db *sql.DB, dialect string, log *zap.SugaredLogger) error {
+	m, err := newMigrator(db, dialect)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	log.Info("Applying migrations up to head")
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("migration up failed: %v", err)
+	}
+	log.Info("Migrations applied successfully")
+	return nil
+}
+
+// MigrateDown откатывает заданное количество миграций.
+func MigrateDown(db *sql.DB, dialect string, steps int, log *zap.SugaredLogger) error {
+	m, err := newMigrator(db, dialect)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	log.Infow("Rolling back migrations", "steps", steps)
+	if err := m.Steps(-steps); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("migration down failed: %v", err)
+	}
+	return nil
+}
+
+// MigrateForce выставляет версию схемы без выполнения SQL, используется для
+// восстановления после миграции, упавшей в dirty-состоянии.
+func MigrateForce(db *sql.DB, dialect string, version int, log *zap.SugaredLogger) error {
+	m, err := newMigrator(db, dialect)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	log.Infow("Forcing schema version", "version", version)
+	return m.Force(version)
+}
+
+// MigrateVersion возвращает текущую применённую версию схемы.
+// migrate.ErrNilVersion означает, что ни одна миграция ещё не применялась.
+func MigrateVersion(db *sql.DB, dialect string) (version uint, dirty bool, err error) {
+	m, err := newMigrator(db, dialect)
+	if err != nil {
+		return 0, false, err
+	}
+	defer m.Close()
+
+	return m.Version()
+}
+
+// latestMigrationVersion сканирует db/migrations/<dialect> и возвращает номер
+// самой старшей миграции, объявленной в каталоге.
+func latestMigrationVersion(dialect string) (uint, error) {
+	entries, err := os.ReadDir(migrationsDir(dialect))
+	if err != nil {
+		return 0, fmt.Errorf("could not read migrations dir: %v", err)
+	}
+
+	var latest uint
+	for _, entry := range entries {
+		matches := migrationFileRe.FindStringSubmatch(filepath.Base(entry.Name()))
+		if matches == nil {
+			continue
+		}
+		v, err := strconv.ParseUint(matches[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		if uint(v) > latest {
+			latest = uint(v)
+		}
+	}
+	return latest, nil
+}
+
+// EnsureSchemaAtHead проверяет, что применённая версия схемы совпадает с
+// последней миграцией в db/migrations/<dialect>, и возвращает ошибку вместо
+// того, чтобы дать приложению стартовать на устаревшей схеме.
+func EnsureSchemaAtHead(db *sql.DB, dialect string) error {
+	version, dirty, err := MigrateVersion(db, dialect)
+	if err != nil {
+		return fmt.Errorf("could not determine schema version: %v", err)
+	}
+	if dirty {
+		return fmt.Errorf("database schema is dirty at version %d, run `migrate force <version>` to resolve", version)
+	}
+
+	latest, err := latestMigrationVersion(dialect)
+	if err != nil {
+		return err
+	}
+	if version != latest {
+		return fmt.Errorf("database schema is at version %d, expected %d; run `migrate up`", version, latest)
+	}
+
+	return nil
+}
+
+// IsFreshDatabase сообщает, не была ли ни одна миграция применена ещё
+// (полезно, чтобы решить, нужно ли засеивать начальные данные).
+func IsFreshDatabase(db *sql.DB, dialect string) (bool, error) {
+	_, _, err := MigrateVersion(db, dialect)
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return false, nil
+}
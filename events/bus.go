@@ -0,0 +1,89 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// TxBeginner is the subset of repository.SubscriptionRepository that Bus
+// needs to open a transaction for the sql dialect's outbox write.
+type TxBeginner interface {
+	BeginTx(ctx context.Context) (*sql.Tx, error)
+}
+
+// Bus publishes subscription lifecycle events alongside the mutation that
+// produced them.
+//
+// With the sql dialect, the mutation and the outbox insert run in the same
+// transaction (true transactional outbox). With amqp/kafka there is no
+// shared transaction with the message broker, so the mutation is committed
+// first and the event is published best-effort immediately after; a publish
+// failure there is logged but does not fail the request, since the mutation
+// already succeeded.
+type Bus struct {
+	repo      TxBeginner
+	outbox    *SQLOutboxPublisher // non-nil only for the sql dialect
+	publisher Publisher           // non-nil only for amqp/kafka dialects
+	log       *zap.SugaredLogger
+}
+
+// NewSQLBus builds a Bus that publishes transactionally into the
+// watermill_messages_<topic> outbox tables.
+func NewSQLBus(repo TxBeginner, log *zap.SugaredLogger) *Bus {
+	return &Bus{repo: repo, outbox: NewSQLOutboxPublisher(), log: log}
+}
+
+// NewBrokerBus builds a Bus that publishes to an external broker (amqp or
+// kafka) after the mutation commits.
+func NewBrokerBus(repo TxBeginner, publisher Publisher, log *zap.SugaredLogger) *Bus {
+	return &Bus{repo: repo, publisher: publisher, log: log}
+}
+
+// Close releases the broker publisher, if this Bus was built with one. The
+// sql dialect's outbox has no connection of its own to close.
+func (b *Bus) Close() error {
+	if b.publisher != nil {
+		return b.publisher.Close()
+	}
+	return nil
+}
+
+// Mutate runs fn, which performs the subscription mutation and returns the
+// event describing it. For the sql dialect, fn runs inside a transaction
+// that the event insert shares; for broker dialects, fn runs directly and
+// the event is published afterwards.
+func (b *Bus) Mutate(ctx context.Context, topic Topic, fn func(ctx context.Context, tx *sql.Tx) (SubscriptionEvent, error)) error {
+	if b.outbox != nil {
+		tx, err := b.repo.BeginTx(ctx)
+		if err != nil {
+			return fmt.Errorf("could not begin transaction: %v", err)
+		}
+
+		event, err := fn(ctx, tx)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if err := b.outbox.PublishTx(tx, topic, event); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("could not write event to outbox: %v", err)
+		}
+
+		return tx.Commit()
+	}
+
+	event, err := fn(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := b.publisher.Publish(ctx, topic, event); err != nil {
+		b.log.Errorw("Failed to publish subscription event", "error", err, "topic", topic, "user_id", event.UserID, "service_name", event.ServiceName)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package events
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/ThreeDotsLabs/watermill"
+	amqp "github.com/ThreeDotsLabs/watermill-amqp/v2/pkg/amqp"
+	kafka "github.com/ThreeDotsLabs/watermill-kafka/v3/pkg/kafka"
+	watermillsql "github.com/ThreeDotsLabs/watermill-sql/v3/pkg/sql"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
+)
+
+// Dialect selects the message bus driver (MQ_DIALECT env var).
+type Dialect string
+
+const (
+	DialectSQL   Dialect = "sql"
+	DialectAMQP  Dialect = "amqp"
+	DialectKafka Dialect = "kafka"
+)
+
+// NewMemoryPubSub builds the in-process pub/sub used instead of any of the
+// above drivers when the service runs with DB_DIALECT=memory: there is no
+// database for the sql dialect's outbox or watermill-sql subscriber to use,
+// whatever MQ_DIALECT says. gochannel.GoChannel only routes messages between
+// ends of the same instance, so callers must use the single returned value
+// as both the subscriber and the publisher(s).
+func NewMemoryPubSub(log watermill.LoggerAdapter) *gochannel.GoChannel {
+	return gochannel.NewGoChannel(gochannel.Config{}, log)
+}
+
+// Config parametrizes the message bus driver.
+type Config struct {
+	Dialect         Dialect
+	AMQPURL         string
+	KafkaBrokers    []string
+	DeadLetterTopic Topic
+}
+
+// NewSubscriber builds the watermill subscriber used to consume subscription
+// lifecycle events, regardless of which dialect produced them.
+func NewSubscriber(cfg Config, db *sql.DB, log watermill.LoggerAdapter) (message.Subscriber, error) {
+	switch cfg.Dialect {
+	case DialectSQL:
+		return watermillsql.NewSubscriber(db, watermillsql.SubscriberConfig{
+			SchemaAdapter:    watermillsql.DefaultPostgreSQLSchema{},
+			OffsetsAdapter:   watermillsql.DefaultPostgreSQLOffsetsAdapter{},
+			InitializeSchema: false, // tables are created by db/migrations
+		}, log)
+	case DialectAMQP:
+		return amqp.NewSubscriber(amqp.NewDurableQueueConfig(cfg.AMQPURL), log)
+	case DialectKafka:
+		return kafka.NewSubscriber(kafka.SubscriberConfig{
+			Brokers:       cfg.KafkaBrokers,
+			Unmarshaler:   kafka.DefaultMarshaler{},
+			ConsumerGroup: "subscription-service",
+		}, log)
+	default:
+		return nil, fmt.Errorf("unknown MQ_DIALECT %q", cfg.Dialect)
+	}
+}
+
+// NewBrokerPublisher builds the watermill publisher for the amqp/kafka
+// dialects. The sql dialect has no message.Publisher of its own: it
+// publishes transactionally through SQLOutboxPublisher instead.
+func NewBrokerPublisher(cfg Config, log watermill.LoggerAdapter) (Publisher, error) {
+	switch cfg.Dialect {
+	case DialectAMQP:
+		pub, err := amqp.NewPublisher(amqp.NewDurableQueueConfig(cfg.AMQPURL), log)
+		if err != nil {
+			return nil, err
+		}
+		return NewWatermillPublisher(pub), nil
+	case DialectKafka:
+		pub, err := kafka.NewPublisher(kafka.PublisherConfig{
+			Brokers:   cfg.KafkaBrokers,
+			Marshaler: kafka.DefaultMarshaler{},
+		}, log)
+		if err != nil {
+			return nil, err
+		}
+		return NewWatermillPublisher(pub), nil
+	default:
+		return nil, fmt.Errorf("dialect %q does not use a broker publisher", cfg.Dialect)
+	}
+}
+
+// NewRawPublisher builds the plain message.Publisher used by the router's
+// dead-letter middleware, for whichever dialect is configured.
+func NewRawPublisher(cfg Config, db *sql.DB, log watermill.LoggerAdapter) (message.Publisher, error) {
+	switch cfg.Dialect {
+	case DialectSQL:
+		return watermillsql.NewPublisher(db, watermillsql.PublisherConfig{
+			SchemaAdapter: watermillsql.DefaultPostgreSQLSchema{},
+		}, log)
+	case DialectAMQP:
+		return amqp.NewPublisher(amqp.NewDurableQueueConfig(cfg.AMQPURL), log)
+	case DialectKafka:
+		return kafka.NewPublisher(kafka.PublisherConfig{
+			Brokers:   cfg.KafkaBrokers,
+			Marshaler: kafka.DefaultMarshaler{},
+		}, log)
+	default:
+		return nil, fmt.Errorf("unknown MQ_DIALECT %q", cfg.Dialect)
+	}
+}
@@ -0,0 +1,31 @@
+package events
+
+import "time"
+
+// Topic names a Watermill topic. They double as the suffix of the
+// watermill_messages_<topic> / watermill_offsets_consumer_<topic> tables
+// created in db/migrations for the sql dialect.
+type Topic string
+
+const (
+	TopicSubscriptionCreated   Topic = "subscription_created"
+	TopicSubscriptionUpdated   Topic = "subscription_updated"
+	TopicSubscriptionCancelled Topic = "subscription_cancelled"
+	TopicSubscriptionRenewed   Topic = "subscription_renewed"
+	TopicSubscriptionRestored  Topic = "subscription_restored"
+)
+
+// SubscriptionEvent is the JSON envelope published for every subscription
+// lifecycle change. Version is the subscription's own monotonic version
+// counter, so a consumer that has already applied version N can discard any
+// redelivery of an event with version <= N instead of double-applying it.
+type SubscriptionEvent struct {
+	Type        Topic     `json:"type"`
+	UserID      string    `json:"user_id"`
+	ServiceName string    `json:"service_name"`
+	Price       int       `json:"price"`
+	StartDate   string    `json:"start_date"`
+	EndDate     string    `json:"end_date,omitempty"`
+	Version     int       `json:"version"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
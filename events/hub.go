@@ -0,0 +1,161 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Filter selects which published events a Subscription receives. Empty
+// fields match anything, mirroring repository.SubscriptionRepository's
+// List/TotalCost filtering semantics.
+type Filter struct {
+	UserID      string
+	ServiceName string
+}
+
+// ParseFilter parses a tendermint-pubsub-style `key=value AND key=value`
+// query into a Filter. Recognized keys are user_id and service_name; an
+// empty query matches every event.
+func ParseFilter(query string) (Filter, error) {
+	var f Filter
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return f, nil
+	}
+
+	for _, clause := range strings.Split(query, " AND ") {
+		key, value, ok := strings.Cut(strings.TrimSpace(clause), "=")
+		if !ok {
+			return Filter{}, fmt.Errorf("invalid filter clause %q, expected key=value", clause)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user_id":
+			f.UserID = value
+		case "service_name":
+			f.ServiceName = value
+		default:
+			return Filter{}, fmt.Errorf("unknown filter key %q", key)
+		}
+	}
+
+	return f, nil
+}
+
+func (f Filter) matches(event SubscriptionEvent) bool {
+	if f.UserID != "" && f.UserID != event.UserID {
+		return false
+	}
+	if f.ServiceName != "" && f.ServiceName != event.ServiceName {
+		return false
+	}
+	return true
+}
+
+// subscriberBuffer bounds how far a Subscription may lag behind Publish
+// before Hub evicts it, so one slow reader can't block delivery to everyone
+// else.
+const subscriberBuffer = 64
+
+// ErrSlowConsumer is set on a Subscription's Err() when Hub evicts it for
+// not draining Events() fast enough.
+var ErrSlowConsumer = errors.New("subscriber evicted: too slow to keep up with events")
+
+// Subscription is a live, filtered feed of subscription lifecycle events
+// returned by Hub.Subscribe. Callers read Events() until Cancelled() closes,
+// then check Err() to tell a normal Hub.Unsubscribe from an eviction.
+type Subscription struct {
+	filter    Filter
+	out       chan SubscriptionEvent
+	cancelled chan struct{}
+	once      sync.Once
+
+	mu  sync.Mutex
+	err error
+}
+
+func (s *Subscription) Events() <-chan SubscriptionEvent { return s.out }
+
+func (s *Subscription) Cancelled() <-chan struct{} { return s.cancelled }
+
+func (s *Subscription) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+func (s *Subscription) cancel(err error) {
+	s.once.Do(func() {
+		s.mu.Lock()
+		s.err = err
+		s.mu.Unlock()
+		close(s.cancelled)
+	})
+}
+
+// Hub fans published subscription lifecycle events out to filtered
+// subscribers in this service (the SSE endpoint, the WebSub notifier). It is
+// in-process and holds no history — a Subscription only sees events
+// published after it was created — unlike the Watermill outbox/broker
+// pipeline, which durably delivers the same events to external consumers.
+type Hub struct {
+	mu   sync.Mutex
+	subs map[*Subscription]struct{}
+	log  *zap.SugaredLogger
+}
+
+func NewHub(log *zap.SugaredLogger) *Hub {
+	return &Hub{subs: make(map[*Subscription]struct{}), log: log}
+}
+
+// Subscribe registers a new Subscription matching filter. Callers must
+// Unsubscribe once done reading, whether they stopped normally or via
+// Cancelled().
+func (h *Hub) Subscribe(filter Filter) *Subscription {
+	sub := &Subscription{
+		filter:    filter,
+		out:       make(chan SubscriptionEvent, subscriberBuffer),
+		cancelled: make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	h.subs[sub] = struct{}{}
+	h.mu.Unlock()
+
+	return sub
+}
+
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	delete(h.subs, sub)
+	h.mu.Unlock()
+	sub.cancel(nil)
+}
+
+// Publish fans event out to every subscriber whose filter matches it. A
+// subscriber whose buffer is already full is evicted instead of allowed to
+// block delivery to everyone else.
+func (h *Hub) Publish(event SubscriptionEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subs {
+		if !sub.filter.matches(event) {
+			continue
+		}
+		select {
+		case sub.out <- event:
+		default:
+			sub.cancel(ErrSlowConsumer)
+			delete(h.subs, sub)
+			h.log.Warnw("Evicted slow event subscriber")
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package events
+
+import (
+	"github.com/ThreeDotsLabs/watermill"
+	"go.uber.org/zap"
+)
+
+// zapLogger adapts *zap.SugaredLogger to watermill.LoggerAdapter so the
+// router and drivers log through the same structured logger as the rest of
+// the application.
+type zapLogger struct {
+	log *zap.SugaredLogger
+}
+
+// NewWatermillLogger wraps log for use as a watermill.LoggerAdapter.
+func NewWatermillLogger(log *zap.SugaredLogger) watermill.LoggerAdapter {
+	return &zapLogger{log: log}
+}
+
+func (l *zapLogger) fields(f watermill.LogFields) []interface{} {
+	args := make([]interface{}, 0, len(f)*2)
+	for k, v := range f {
+		args = append(args, k, v)
+	}
+	return args
+}
+
+func (l *zapLogger) Error(msg string, err error, f watermill.LogFields) {
+	l.log.Errorw(msg, append(l.fields(f), "error", err)...)
+}
+
+func (l *zapLogger) Info(msg string, f watermill.LogFields) {
+	l.log.Infow(msg, l.fields(f)...)
+}
+
+func (l *zapLogger) Debug(msg string, f watermill.LogFields) {
+	l.log.Debugw(msg, l.fields(f)...)
+}
+
+func (l *zapLogger) Trace(msg string, f watermill.LogFields) {
+	l.log.Debugw(msg, l.fields(f)...)
+}
+
+func (l *zapLogger) With(f watermill.LogFields) watermill.LoggerAdapter {
+	return &zapLogger{log: l.log.With(l.fields(f)...)}
+}
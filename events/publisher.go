@@ -0,0 +1,64 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/google/uuid"
+)
+
+// Publisher publishes a SubscriptionEvent to a broker outside of any
+// database transaction. It backs the amqp/kafka dialects.
+type Publisher interface {
+	Publish(ctx context.Context, topic Topic, event SubscriptionEvent) error
+	Close() error
+}
+
+// watermillPublisher adapts a watermill message.Publisher (amqp or kafka) to
+// Publisher.
+type watermillPublisher struct {
+	pub message.Publisher
+}
+
+func NewWatermillPublisher(pub message.Publisher) Publisher {
+	return &watermillPublisher{pub: pub}
+}
+
+func (p *watermillPublisher) Publish(_ context.Context, topic Topic, event SubscriptionEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("could not marshal event: %v", err)
+	}
+
+	msg := message.NewMessage(uuid.NewString(), payload)
+	return p.pub.Publish(string(topic), msg)
+}
+
+func (p *watermillPublisher) Close() error {
+	return p.pub.Close()
+}
+
+// SQLOutboxPublisher writes events directly into the watermill_messages_<topic>
+// table (watermill-sql's own Postgres schema), inside the same transaction as
+// the subscription mutation that produced them — the transactional outbox
+// pattern. The watermill-sql subscriber on the consuming side then delivers
+// them exactly as if they had gone through message.Publisher.Publish.
+type SQLOutboxPublisher struct{}
+
+func NewSQLOutboxPublisher() *SQLOutboxPublisher {
+	return &SQLOutboxPublisher{}
+}
+
+func (p *SQLOutboxPublisher) PublishTx(tx *sql.Tx, topic Topic, event SubscriptionEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("could not marshal event: %v", err)
+	}
+
+	query := fmt.Sprintf(`INSERT INTO watermill_messages_%s (uuid, payload, metadata) VALUES ($1, $2, $3)`, topic)
+	_, err = tx.Exec(query, uuid.NewString(), payload, []byte("{}"))
+	return err
+}
@@ -0,0 +1,36 @@
+package events
+
+import (
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
+)
+
+// NewRouter builds a watermill router with a bounded retry policy and a
+// dead-letter topic for handlers that keep failing after every retry.
+func NewRouter(log watermill.LoggerAdapter, deadLetterPublisher message.Publisher, deadLetterTopic Topic) (*message.Router, error) {
+	router, err := message.NewRouter(message.RouterConfig{}, log)
+	if err != nil {
+		return nil, err
+	}
+
+	poisonQueue, err := middleware.PoisonQueue(deadLetterPublisher, string(deadLetterTopic))
+	if err != nil {
+		return nil, err
+	}
+
+	router.AddMiddleware(
+		middleware.Retry{
+			MaxRetries:      3,
+			InitialInterval: 100 * time.Millisecond,
+			MaxInterval:     5 * time.Second,
+			Multiplier:      2,
+			Logger:          log,
+		}.Middleware,
+		poisonQueue,
+	)
+
+	return router, nil
+}
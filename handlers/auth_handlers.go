@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"subscription-service/auth"
+	"subscription-service/logger"
+	"subscription-service/models"
+	"subscription-service/repository"
+)
+
+// AuthHandler issues and revokes the JWTs that AuthRequired validates on
+// every other route.
+type AuthHandler struct {
+	Users     repository.UserRepository
+	Blacklist repository.TokenBlacklistRepository
+	Issuer    *auth.Issuer
+}
+
+func NewAuthHandler(users repository.UserRepository, blacklist repository.TokenBlacklistRepository, issuer *auth.Issuer) *AuthHandler {
+	return &AuthHandler{Users: users, Blacklist: blacklist, Issuer: issuer}
+}
+
+// Signup регистрирует нового пользователя
+// @Summary Register a new user
+// @Description Create a user account with a bcrypt-hashed password.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.SignupRequest true "Signup Details"
+// @Success 201 {object} models.User
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/signup [post]
+func (h *AuthHandler) Signup(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context())
+
+	var req models.SignupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warnw("Failed to bind JSON on signup", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		log.Errorw("Failed to hash password", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	user, err := h.Users.Create(c.Request.Context(), req.Email, hash)
+	if err != nil {
+		if errors.Is(err, repository.ErrEmailTaken) {
+			log.Infow("Signup attempted with taken email", "email", req.Email)
+			c.JSON(http.StatusConflict, gin.H{"error": "email already registered"})
+			return
+		}
+		log.Errorw("Database error on signup", "error", err, "email", req.Email)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	log.Infow("User registered", "user_id", user.ID, "email", user.Email)
+	c.JSON(http.StatusCreated, user)
+}
+
+// Login аутентифицирует пользователя и выдаёт пару токенов
+// @Summary Log in
+// @Description Exchange an email/password pair for an access/refresh token pair.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.LoginRequest true "Login Details"
+// @Success 200 {object} models.TokenResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/login [post]
+func (h *AuthHandler) Login(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context())
+
+	var req models.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warnw("Failed to bind JSON on login", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.Users.GetByEmail(c.Request.Context(), req.Email)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid email or password"})
+			return
+		}
+		log.Errorw("Database error on login", "error", err, "email", req.Email)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	if err := auth.CheckPassword(user.PasswordHash, req.Password); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid email or password"})
+		return
+	}
+
+	tokens, err := h.issueTokens(user)
+	if err != nil {
+		log.Errorw("Failed to issue tokens on login", "error", err, "user_id", user.ID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	log.Infow("User logged in", "user_id", user.ID)
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Refresh обменивает refresh-токен на новую пару токенов
+// @Summary Refresh a token pair
+// @Description Exchange a still-valid refresh token for a new access/refresh pair. The refresh token is revoked in the same call, so it cannot be reused.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.RefreshRequest true "Refresh Token"
+// @Success 200 {object} models.TokenResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context())
+
+	var req models.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warnw("Failed to bind JSON on refresh", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims, err := h.Issuer.ParseRefreshToken(req.RefreshToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid refresh token"})
+		return
+	}
+
+	revoked, err := h.Blacklist.IsRevoked(c.Request.Context(), claims.ID)
+	if err != nil {
+		log.Errorw("Failed to check token blacklist on refresh", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	if revoked {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid refresh token"})
+		return
+	}
+
+	user, err := h.Users.GetByID(c.Request.Context(), claims.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid refresh token"})
+			return
+		}
+		log.Errorw("Database error on refresh", "error", err, "user_id", claims.UserID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	if err := h.Blacklist.Revoke(c.Request.Context(), claims.ID, claims.ExpiresAt.Time); err != nil {
+		log.Errorw("Failed to revoke used refresh token", "error", err, "user_id", user.ID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	tokens, err := h.issueTokens(user)
+	if err != nil {
+		log.Errorw("Failed to issue tokens on refresh", "error", err, "user_id", user.ID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	log.Infow("Tokens refreshed", "user_id", user.ID)
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Logout отзывает текущий access-токен
+// @Summary Log out
+// @Description Revoke the access token used on this request, so it cannot authorize further calls before it expires.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/logout [post]
+// @Security BearerAuth
+func (h *AuthHandler) Logout(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context())
+
+	claims, ok := auth.ClaimsFromContext(c.Request.Context())
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if err := h.Blacklist.Revoke(c.Request.Context(), claims.ID, claims.ExpiresAt.Time); err != nil {
+		log.Errorw("Failed to revoke token on logout", "error", err, "user_id", claims.UserID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	log.Infow("User logged out", "user_id", claims.UserID)
+	c.JSON(http.StatusOK, gin.H{"message": "logged out"})
+}
+
+// issueTokens signs a fresh access/refresh pair for user.
+func (h *AuthHandler) issueTokens(user *models.User) (models.TokenResponse, error) {
+	access, err := h.Issuer.IssueAccessToken(user.ID, user.IsAdmin)
+	if err != nil {
+		return models.TokenResponse{}, err
+	}
+	refresh, err := h.Issuer.IssueRefreshToken(user.ID, user.IsAdmin)
+	if err != nil {
+		return models.TokenResponse{}, err
+	}
+	return models.TokenResponse{AccessToken: access, RefreshToken: refresh}, nil
+}
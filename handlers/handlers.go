@@ -1,12 +1,21 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
-	"github.com/gin-gonic/gin"
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"subscription-service/auth"
+	"subscription-service/events"
 	"subscription-service/logger"
 	"subscription-service/models"
-	"time"
+	"subscription-service/query"
+	"subscription-service/repository"
 )
 
 // Вспомогательные функции для работы с датами
@@ -17,20 +26,45 @@ func parseMonthYear(dateStr string) (time.Time, error) {
 	return time.Parse("01-2006", dateStr)
 }
 
-func formatMonthYear(t time.Time) string {
-	return t.Format("01-2006")
-}
-
 func toFirstDayOfMonth(t time.Time) time.Time {
 	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
 }
 
 type SubscriptionHandler struct {
-	DB *sql.DB
+	Repo   repository.SubscriptionRepository
+	Events *events.Bus
+	Jobs   repository.ImportJobRepository
+	Hub    *events.Hub
+	WebSub repository.WebSubRepository
 }
 
-func NewSubscriptionHandler(db *sql.DB) *SubscriptionHandler {
-	return &SubscriptionHandler{DB: db}
+func NewSubscriptionHandler(repo repository.SubscriptionRepository, bus *events.Bus, jobs repository.ImportJobRepository, hub *events.Hub, webSub repository.WebSubRepository) *SubscriptionHandler {
+	return &SubscriptionHandler{Repo: repo, Events: bus, Jobs: jobs, Hub: hub, WebSub: webSub}
+}
+
+// actor returns the authenticated caller's user ID, for attribution in the
+// subscription_events audit trail. AuthRequired always runs ahead of the
+// routes that call this, so claims are expected to be present.
+func actor(ctx context.Context) string {
+	claims, ok := auth.ClaimsFromContext(ctx)
+	if !ok {
+		return ""
+	}
+	return claims.UserID
+}
+
+// toEvent builds the lifecycle event published for a subscription mutation.
+func toEvent(topic events.Topic, sub *models.Subscription) events.SubscriptionEvent {
+	return events.SubscriptionEvent{
+		Type:        topic,
+		UserID:      sub.UserID,
+		ServiceName: sub.ServiceName,
+		Price:       sub.Price,
+		StartDate:   sub.StartDate,
+		EndDate:     sub.EndDate,
+		Version:     sub.Version,
+		OccurredAt:  time.Now(),
+	}
 }
 
 // CreateSubscription создает новую подписку
@@ -47,26 +81,28 @@ func NewSubscriptionHandler(db *sql.DB) *SubscriptionHandler {
 // @Failure 500 {object} map[string]string
 // @Router /subscriptions/{user_id}/{service_name} [post]
 func (h *SubscriptionHandler) CreateSubscription(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context())
+
 	var key models.CompositeKey
 	if err := c.ShouldBindUri(&key); err != nil {
-		logger.Log.Warnw("Failed to bind URI parameters", "error", err, "user_id", key.UserID, "service_name", key.ServiceName)
+		log.Warnw("Failed to bind URI parameters", "error", err, "user_id", key.UserID, "service_name", key.ServiceName)
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	var req models.SubscriptionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Log.Warnw("Failed to bind JSON", "error", err, "user_id", key.UserID)
+		log.Warnw("Failed to bind JSON", "error", err, "user_id", key.UserID)
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	logger.Log.Debugw("Attempting to create subscription", "user_id", key.UserID, "service_name", req.ServiceName, "request", req)
+	log.Debugw("Attempting to create subscription", "user_id", key.UserID, "service_name", req.ServiceName, "request", req)
 
 	// Парсим даты
 	startDate, err := parseMonthYear(req.StartDate)
 	if err != nil {
-		logger.Log.Warnw("Invalid start_date format", "error", err, "start_date", req.StartDate)
+		log.Warnw("Invalid start_date format", "error", err, "start_date", req.StartDate)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_date format, expected MM-YYYY"})
 		return
 	}
@@ -76,7 +112,7 @@ func (h *SubscriptionHandler) CreateSubscription(c *gin.Context) {
 	if req.EndDate != "" {
 		endDate, err := parseMonthYear(req.EndDate)
 		if err != nil {
-			logger.Log.Warnw("Invalid end_date format", "error", err, "end_date", req.EndDate)
+			log.Warnw("Invalid end_date format", "error", err, "end_date", req.EndDate)
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_date format, expected MM-YYYY"})
 			return
 		}
@@ -84,44 +120,22 @@ func (h *SubscriptionHandler) CreateSubscription(c *gin.Context) {
 		endDatePtr = &endDate
 	}
 
-	query := `
-		INSERT INTO subscriptions (service_name, price, user_id, start_date, end_date)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING service_name, price, user_id, start_date, end_date
-	`
-
-	var sub models.Subscription
-	var dbEndDate sql.NullTime
-
-	err = h.DB.QueryRow(
-		query,
-		req.ServiceName,
-		req.Price,
-		key.UserID,
-		startDate,
-		endDatePtr,
-	).Scan(
-		&sub.ServiceName,
-		&sub.Price,
-		&sub.UserID,
-		&startDate,
-		&dbEndDate,
-	)
-
+	var sub *models.Subscription
+	err = h.Events.Mutate(c.Request.Context(), events.TopicSubscriptionCreated, func(ctx context.Context, tx *sql.Tx) (events.SubscriptionEvent, error) {
+		var err error
+		sub, err = h.Repo.Create(ctx, tx, actor(ctx), key.UserID, req.ServiceName, req.Price, startDate, endDatePtr)
+		if err != nil {
+			return events.SubscriptionEvent{}, err
+		}
+		return toEvent(events.TopicSubscriptionCreated, sub), nil
+	})
 	if err != nil {
-		logger.Log.Errorw("Database error on subscription creation", "error", err, "user_id", key.UserID, "service_name", req.ServiceName)
+		log.Errorw("Database error on subscription creation", "error", err, "user_id", key.UserID, "service_name", req.ServiceName)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		return
 	}
 
-	// Форматируем даты для ответа
-	sub.StartDate = formatMonthYear(startDate)
-	if dbEndDate.Valid {
-		endDate := formatMonthYear(dbEndDate.Time)
-		sub.EndDate = endDate
-	}
-
-	logger.Log.Infow("Subscription created successfully", "user_id", sub.UserID, "service_name", sub.ServiceName)
+	log.Infow("Subscription created successfully", "user_id", sub.UserID, "service_name", sub.ServiceName)
 	c.JSON(http.StatusCreated, sub)
 }
 
@@ -140,26 +154,28 @@ func (h *SubscriptionHandler) CreateSubscription(c *gin.Context) {
 // @Failure 500 {object} map[string]string
 // @Router /subscriptions/{user_id}/{service_name} [put]
 func (h *SubscriptionHandler) UpdateSubscription(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context())
+
 	var key models.CompositeKey
 	if err := c.ShouldBindUri(&key); err != nil {
-		logger.Log.Warnw("Failed to bind URI parameters on update", "error", err, "user_id", key.UserID, "service_name", key.ServiceName)
+		log.Warnw("Failed to bind URI parameters on update", "error", err, "user_id", key.UserID, "service_name", key.ServiceName)
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	var req models.SubscriptionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Log.Warnw("Failed to bind JSON on update", "error", err, "user_id", key.UserID)
+		log.Warnw("Failed to bind JSON on update", "error", err, "user_id", key.UserID)
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	logger.Log.Debugw("Attempting to update subscription", "user_id", key.UserID, "service_name", req.ServiceName, "request", req)
+	log.Debugw("Attempting to update subscription", "user_id", key.UserID, "service_name", req.ServiceName, "request", req)
 
 	// Парсим даты
 	startDate, err := parseMonthYear(req.StartDate)
 	if err != nil {
-		logger.Log.Warnw("Invalid start_date format on update", "error", err, "start_date", req.StartDate)
+		log.Warnw("Invalid start_date format on update", "error", err, "start_date", req.StartDate)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_date format, expected MM-YYYY"})
 		return
 	}
@@ -169,7 +185,7 @@ func (h *SubscriptionHandler) UpdateSubscription(c *gin.Context) {
 	if req.EndDate != "" {
 		endDate, err := parseMonthYear(req.EndDate)
 		if err != nil {
-			logger.Log.Warnw("Invalid end_date format on update", "error", err, "end_date", req.EndDate)
+			log.Warnw("Invalid end_date format on update", "error", err, "end_date", req.EndDate)
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_date format, expected MM-YYYY"})
 			return
 		}
@@ -177,52 +193,27 @@ func (h *SubscriptionHandler) UpdateSubscription(c *gin.Context) {
 		endDatePtr = &endDate
 	}
 
-	query := `
-		UPDATE subscriptions 
-		SET 
-			price = $1,
-			start_date = $2,
-			end_date = $3
-		WHERE user_id = $4 AND service_name = $5
-		RETURNING service_name, price, user_id, start_date, end_date
-	`
-
-	var sub models.Subscription
-	var dbEndDate sql.NullTime
-
-	err = h.DB.QueryRow(
-		query,
-		req.Price,
-		startDate,
-		endDatePtr,
-		key.UserID,
-		req.ServiceName,
-	).Scan(
-		&sub.ServiceName,
-		&sub.Price,
-		&sub.UserID,
-		&startDate,
-		&dbEndDate,
-	)
-
+	var sub *models.Subscription
+	err = h.Events.Mutate(c.Request.Context(), events.TopicSubscriptionUpdated, func(ctx context.Context, tx *sql.Tx) (events.SubscriptionEvent, error) {
+		var err error
+		sub, err = h.Repo.Update(ctx, tx, actor(ctx), key.UserID, req.ServiceName, req.Price, startDate, endDatePtr)
+		if err != nil {
+			return events.SubscriptionEvent{}, err
+		}
+		return toEvent(events.TopicSubscriptionUpdated, sub), nil
+	})
 	if err != nil {
-		if err == sql.ErrNoRows {
-			logger.Log.Infow("Subscription not found for update", "user_id", key.UserID, "service_name", req.ServiceName)
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Infow("Subscription not found for update", "user_id", key.UserID, "service_name", req.ServiceName)
 			c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
 			return
 		}
-		logger.Log.Errorw("Database error on subscription update", "error", err, "user_id", key.UserID, "service_name", req.ServiceName)
+		log.Errorw("Database error on subscription update", "error", err, "user_id", key.UserID, "service_name", req.ServiceName)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		return
 	}
 
-	// Форматируем даты для ответа
-	sub.StartDate = formatMonthYear(startDate)
-	if dbEndDate.Valid {
-		sub.EndDate = formatMonthYear(dbEndDate.Time)
-	}
-
-	logger.Log.Infow("Subscription updated successfully", "user_id", sub.UserID, "service_name", sub.ServiceName)
+	log.Infow("Subscription updated successfully", "user_id", sub.UserID, "service_name", sub.ServiceName)
 	c.JSON(http.StatusOK, sub)
 }
 
@@ -239,57 +230,36 @@ func (h *SubscriptionHandler) UpdateSubscription(c *gin.Context) {
 // @Failure 500 {object} map[string]string
 // @Router /subscriptions/{user_id}/{service_name} [get]
 func (h *SubscriptionHandler) GetSubscription(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context())
+
 	var key models.CompositeKey
 	if err := c.ShouldBindUri(&key); err != nil {
-		logger.Log.Warnw("Failed to bind URI parameters on get", "error", err, "user_id", key.UserID, "service_name", key.ServiceName)
+		log.Warnw("Failed to bind URI parameters on get", "error", err, "user_id", key.UserID, "service_name", key.ServiceName)
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	logger.Log.Debugw("Fetching subscription", "user_id", key.UserID, "service_name", key.ServiceName)
-
-	var sub models.Subscription
-	query := `
-		SELECT service_name, price, user_id, start_date, end_date 
-		FROM subscriptions 
-		WHERE user_id = $1 AND service_name = $2
-	`
-
-	row := h.DB.QueryRow(query, key.UserID, key.ServiceName)
-
-	var startDate time.Time
-	var endDate sql.NullTime
-	err := row.Scan(
-		&sub.ServiceName,
-		&sub.Price,
-		&sub.UserID,
-		&startDate,
-		&endDate,
-	)
+	log.Debugw("Fetching subscription", "user_id", key.UserID, "service_name", key.ServiceName)
 
+	sub, err := h.Repo.Get(c.Request.Context(), key.UserID, key.ServiceName)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			logger.Log.Infow("Subscription not found", "user_id", key.UserID, "service_name", key.ServiceName)
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Infow("Subscription not found", "user_id", key.UserID, "service_name", key.ServiceName)
 			c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
 			return
 		}
-		logger.Log.Errorw("Database error on subscription fetch", "error", err, "user_id", key.UserID, "service_name", key.ServiceName)
+		log.Errorw("Database error on subscription fetch", "error", err, "user_id", key.UserID, "service_name", key.ServiceName)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		return
 	}
 
-	sub.StartDate = formatMonthYear(startDate)
-	if endDate.Valid {
-		sub.EndDate = formatMonthYear(endDate.Time)
-	}
-
-	logger.Log.Debugw("Subscription found", "user_id", sub.UserID, "service_name", sub.ServiceName)
+	log.Debugw("Subscription found", "user_id", sub.UserID, "service_name", sub.ServiceName)
 	c.JSON(http.StatusOK, sub)
 }
 
 // DeleteSubscription удаляет подписку
 // @Summary Delete a subscription
-// @Description Delete a specific subscription by user ID and service name.
+// @Description Soft-delete a subscription by user ID and service name: the row is kept (deleted_at is set) so it can be restored via POST .../restore and still shows up in GET .../history.
 // @Tags subscriptions
 // @Produce json
 // @Param user_id path string true "User UUID" Format(uuid)
@@ -300,200 +270,232 @@ func (h *SubscriptionHandler) GetSubscription(c *gin.Context) {
 // @Failure 500 {object} map[string]string
 // @Router /subscriptions/{user_id}/{service_name} [delete]
 func (h *SubscriptionHandler) DeleteSubscription(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context())
+
 	var key models.CompositeKey
 	if err := c.ShouldBindUri(&key); err != nil {
-		logger.Log.Warnw("Failed to bind URI parameters on delete", "error", err, "user_id", key.UserID, "service_name", key.ServiceName)
+		log.Warnw("Failed to bind URI parameters on delete", "error", err, "user_id", key.UserID, "service_name", key.ServiceName)
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	logger.Log.Debugw("Deleting subscription", "user_id", key.UserID, "service_name", key.ServiceName)
-
-	query := `
-		DELETE FROM subscriptions 
-		WHERE user_id = $1 AND service_name = $2
-		RETURNING service_name
-	`
-
-	var serviceName string
-	err := h.DB.QueryRow(query, key.UserID, key.ServiceName).Scan(&serviceName)
+	log.Debugw("Deleting subscription", "user_id", key.UserID, "service_name", key.ServiceName)
 
+	err := h.Events.Mutate(c.Request.Context(), events.TopicSubscriptionCancelled, func(ctx context.Context, tx *sql.Tx) (events.SubscriptionEvent, error) {
+		sub, err := h.Repo.Delete(ctx, tx, actor(ctx), key.UserID, key.ServiceName)
+		if err != nil {
+			return events.SubscriptionEvent{}, err
+		}
+		return toEvent(events.TopicSubscriptionCancelled, sub), nil
+	})
 	if err != nil {
-		if err == sql.ErrNoRows {
-			logger.Log.Infow("Subscription not found for deletion", "user_id", key.UserID, "service_name", key.ServiceName)
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Infow("Subscription not found for deletion", "user_id", key.UserID, "service_name", key.ServiceName)
 			c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
 			return
 		}
-		logger.Log.Errorw("Database error on subscription deletion", "error", err, "user_id", key.UserID, "service_name", key.ServiceName)
+		log.Errorw("Database error on subscription deletion", "error", err, "user_id", key.UserID, "service_name", key.ServiceName)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		return
 	}
 
-	logger.Log.Infow("Subscription deleted successfully", "user_id", key.UserID, "service_name", serviceName)
+	log.Infow("Subscription deleted successfully", "user_id", key.UserID, "service_name", key.ServiceName)
 	c.JSON(http.StatusOK, gin.H{
 		"message":      "subscription deleted",
-		"service_name": serviceName,
+		"service_name": key.ServiceName,
 		"user_id":      key.UserID,
 	})
 }
 
+// RestoreSubscription отменяет мягкое удаление подписки
+// @Summary Restore a soft-deleted subscription
+// @Description Clear deleted_at on a soft-deleted subscription, making it active again.
+// @Tags subscriptions
+// @Produce json
+// @Param user_id path string true "User UUID" Format(uuid)
+// @Param service_name path string true "Service Name"
+// @Success 200 {object} models.Subscription
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /subscriptions/{user_id}/{service_name}/restore [post]
+func (h *SubscriptionHandler) RestoreSubscription(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context())
+
+	var key models.CompositeKey
+	if err := c.ShouldBindUri(&key); err != nil {
+		log.Warnw("Failed to bind URI parameters on restore", "error", err, "user_id", key.UserID, "service_name", key.ServiceName)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Debugw("Restoring subscription", "user_id", key.UserID, "service_name", key.ServiceName)
+
+	var sub *models.Subscription
+	err := h.Events.Mutate(c.Request.Context(), events.TopicSubscriptionRestored, func(ctx context.Context, tx *sql.Tx) (events.SubscriptionEvent, error) {
+		var err error
+		sub, err = h.Repo.Restore(ctx, tx, actor(ctx), key.UserID, key.ServiceName)
+		if err != nil {
+			return events.SubscriptionEvent{}, err
+		}
+		return toEvent(events.TopicSubscriptionRestored, sub), nil
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Infow("Subscription not found for restore", "user_id", key.UserID, "service_name", key.ServiceName)
+			c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
+			return
+		}
+		if errors.Is(err, repository.ErrNotDeleted) {
+			log.Infow("Subscription is not deleted, cannot restore", "user_id", key.UserID, "service_name", key.ServiceName)
+			c.JSON(http.StatusConflict, gin.H{"error": "subscription is not deleted"})
+			return
+		}
+		log.Errorw("Database error on subscription restore", "error", err, "user_id", key.UserID, "service_name", key.ServiceName)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	log.Infow("Subscription restored successfully", "user_id", sub.UserID, "service_name", sub.ServiceName)
+	c.JSON(http.StatusOK, sub)
+}
+
+// GetSubscriptionHistory возвращает историю изменений подписки
+// @Summary Get a subscription's audit history
+// @Description Returns every Create/Update/Delete/Restore mutation recorded for a subscription, oldest first.
+// @Tags subscriptions
+// @Produce json
+// @Param user_id path string true "User UUID" Format(uuid)
+// @Param service_name path string true "Service Name"
+// @Success 200 {array} models.SubscriptionAuditEntry
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /subscriptions/{user_id}/{service_name}/history [get]
+func (h *SubscriptionHandler) GetSubscriptionHistory(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context())
+
+	var key models.CompositeKey
+	if err := c.ShouldBindUri(&key); err != nil {
+		log.Warnw("Failed to bind URI parameters on history", "error", err, "user_id", key.UserID, "service_name", key.ServiceName)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	history, err := h.Repo.History(c.Request.Context(), key.UserID, key.ServiceName)
+	if err != nil {
+		log.Errorw("Database error on subscription history", "error", err, "user_id", key.UserID, "service_name", key.ServiceName)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	log.Debugw("Returning subscription history", "user_id", key.UserID, "service_name", key.ServiceName, "count", len(history))
+	c.JSON(http.StatusOK, history)
+}
+
 // ListSubscriptions возвращает список подписок с фильтрацией
 // @Summary List subscriptions
-// @Description Get a list of subscriptions with optional filtering by user ID and/or service name.
+// @Description Get a paginated list of subscriptions, optionally filtered with the `q` temporal query language (e.g. `q=user_id='...' AND active_on='07-2025'`) and sorted with order_by.
 // @Tags subscriptions
 // @Produce json
-// @Param user_id query string false "Filter by User UUID" Format(uuid)
-// @Param service_name query string false "Filter by Service Name"
-// @Success 200 {array} models.Subscription
+// @Param q query string false "Filter expression, e.g. user_id='...' AND active_on='07-2025'"
+// @Param order_by query string false "Sort column, optionally followed by asc|desc (default asc)"
+// @Param page query int false "Page number, starting at 1" default(1)
+// @Param per_page query int false "Results per page (max 100)" default(20)
+// @Param include_deleted query bool false "Include soft-deleted subscriptions (admin only)"
+// @Success 200 {object} models.SubscriptionPage
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /subscriptions [get]
 func (h *SubscriptionHandler) ListSubscriptions(c *gin.Context) {
-	userID := c.Query("user_id")
-	serviceName := c.Query("service_name")
-
-	logger.Log.Debugw("Listing subscriptions", "user_id", userID, "service_name", serviceName)
-
-	var rows *sql.Rows
-	var err error
-
-	switch {
-	case userID != "" && serviceName != "":
-		query := `
-			SELECT service_name, price, user_id, start_date, end_date
-			FROM subscriptions
-			WHERE user_id = $1 AND service_name = $2
-		`
-		rows, err = h.DB.Query(query, userID, serviceName)
-	case userID != "":
-		query := `
-			SELECT service_name, price, user_id, start_date, end_date
-			FROM subscriptions
-			WHERE user_id = $1
-		`
-		rows, err = h.DB.Query(query, userID)
-	case serviceName != "":
-		query := `
-			SELECT service_name, price, user_id, start_date, end_date
-			FROM subscriptions
-			WHERE service_name = $1
-		`
-		rows, err = h.DB.Query(query, serviceName)
-	default:
-		query := `
-			SELECT service_name, price, user_id, start_date, end_date
-			FROM subscriptions
-		`
-		rows, err = h.DB.Query(query)
+	log := logger.FromContext(c.Request.Context())
+
+	filter, err := query.Parse(c.Query("q"))
+	if err != nil {
+		log.Warnw("Invalid q filter on list", "error", err, "q", c.Query("q"))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
+	order, err := query.ParseOrderBy(c.Query("order_by"))
 	if err != nil {
-		logger.Log.Errorw("Database error on listing subscriptions", "error", err, "user_id", userID, "service_name", serviceName)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		log.Warnw("Invalid order_by on list", "error", err, "order_by", c.Query("order_by"))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	defer rows.Close()
-
-	var subscriptions []models.Subscription
-	for rows.Next() {
-		var sub models.Subscription
-		var startDate time.Time
-		var endDate sql.NullTime
-		err := rows.Scan(
-			&sub.ServiceName,
-			&sub.Price,
-			&sub.UserID,
-			&startDate,
-			&endDate,
-		)
+
+	page, perPage, err := query.ParsePage(c.Query("page"), c.Query("per_page"))
+	if err != nil {
+		log.Warnw("Invalid pagination params on list", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	includeDeleted := false
+	if raw := c.Query("include_deleted"); raw != "" {
+		includeDeleted, err = strconv.ParseBool(raw)
 		if err != nil {
-			logger.Log.Errorw("Error scanning subscription row", "error", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			log.Warnw("Invalid include_deleted on list", "error", err, "include_deleted", raw)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid include_deleted"})
 			return
 		}
-		sub.StartDate = formatMonthYear(startDate)
-		if endDate.Valid {
-			sub.EndDate = formatMonthYear(endDate.Time)
+		if includeDeleted {
+			claims, ok := auth.ClaimsFromContext(c.Request.Context())
+			if !ok || !claims.IsAdmin {
+				c.JSON(http.StatusForbidden, gin.H{"error": "include_deleted is admin only"})
+				return
+			}
 		}
-		subscriptions = append(subscriptions, sub)
 	}
 
-	if err = rows.Err(); err != nil {
-		logger.Log.Errorw("Error after iterating subscription rows", "error", err)
+	log.Debugw("Listing subscriptions", "q", c.Query("q"), "order_by", order, "page", page, "per_page", perPage, "include_deleted", includeDeleted)
+
+	subscriptions, total, err := h.Repo.List(c.Request.Context(), filter, order, page, perPage, includeDeleted)
+	if err != nil {
+		log.Errorw("Database error on listing subscriptions", "error", err, "q", c.Query("q"))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		return
 	}
 
-	logger.Log.Debugw("Returning subscriptions list", "count", len(subscriptions))
-	c.JSON(http.StatusOK, subscriptions)
+	log.Debugw("Returning subscriptions list", "count", len(subscriptions), "total", total)
+	c.JSON(http.StatusOK, models.SubscriptionPage{
+		Subscriptions: subscriptions,
+		Page:          page,
+		PerPage:       perPage,
+		Total:         total,
+	})
 }
 
 // GetTotalCost вычисляет суммарную стоимость подписок
 // @Summary Calculate total cost
-// @Description Calculates the total cost of active subscriptions for a given period (inclusive). Optionally filtered by user and service. Dates must be in MM-YYYY format.
+// @Description Calculates the total cost of subscriptions matching the `q` temporal query language filter, e.g. `q=user_id='...' AND active_between='01-2025..06-2025'`.
 // @Tags analytics
 // @Produce json
-// @Param user_id query string false "Filter by User UUID" Format(uuid)
-// @Param service_name query string false "Filter by Service Name"
-// @Param start_date query string true "Start Period (MM-YYYY)"
-// @Param end_date query string true "End Period (MM-YYYY)"
+// @Param q query string false "Filter expression, e.g. user_id='...' AND active_between='01-2025..06-2025'"
 // @Success 200 {object} models.TotalCostResponse
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /subscriptions/total [get]
 func (h *SubscriptionHandler) GetTotalCost(c *gin.Context) {
-	var params models.FilterParams
-	if err := c.ShouldBindQuery(&params); err != nil {
-		logger.Log.Warnw("Failed to bind query parameters on total cost", "error", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
+	log := logger.FromContext(c.Request.Context())
 
-	logger.Log.Debugw("Calculating total cost", "params", params)
-
-	// Парсим даты из формата "MM-YYYY"
-	startDate, err := parseMonthYear(params.StartDate)
+	filter, err := query.Parse(c.Query("q"))
 	if err != nil {
-		logger.Log.Warnw("Invalid start_date format on total cost", "error", err, "start_date", params.StartDate)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_date format, expected MM-YYYY"})
+		log.Warnw("Invalid q filter on total cost", "error", err, "q", c.Query("q"))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	startDate = toFirstDayOfMonth(startDate)
 
-	endDate, err := parseMonthYear(params.EndDate)
-	if err != nil {
-		logger.Log.Warnw("Invalid end_date format on total cost", "error", err, "end_date", params.EndDate)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_date format, expected MM-YYYY"})
-		return
-	}
-	endDate = toFirstDayOfMonth(endDate)
-
-	query := `
-		SELECT COALESCE(SUM(price), 0)
-		FROM subscriptions
-		WHERE start_date <= $2 AND (end_date >= $1 OR end_date IS NULL)
-	`
-	args := []interface{}{startDate, endDate}
-
-	if params.UserID != "" {
-		query += " AND user_id = $3"
-		args = append(args, params.UserID)
-		if params.ServiceName != "" {
-			query += " AND service_name = $4"
-			args = append(args, params.ServiceName)
-		}
-	} else if params.ServiceName != "" {
-		query += " AND service_name = $3"
-		args = append(args, params.ServiceName)
-	}
+	log.Debugw("Calculating total cost", "q", c.Query("q"))
 
-	var totalCost int
-	err = h.DB.QueryRow(query, args...).Scan(&totalCost)
+	totalCost, err := h.Repo.TotalCost(c.Request.Context(), filter)
 	if err != nil {
-		logger.Log.Errorw("Database error on total cost calculation", "error", err, "query", query, "args", args)
+		log.Errorw("Database error on total cost calculation", "error", err, "q", c.Query("q"))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		return
 	}
 
-	logger.Log.Infow("Total cost calculated", "total_cost", totalCost, "user_id", params.UserID, "service_name", params.ServiceName)
+	log.Infow("Total cost calculated", "total_cost", totalCost, "q", c.Query("q"))
 	c.JSON(http.StatusOK, models.TotalCostResponse{TotalCost: totalCost})
 }
@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"subscription-service/events"
+	"subscription-service/logger"
+	"subscription-service/models"
+	"subscription-service/repository"
+)
+
+// ImportSubscriptions принимает CSV или JSON файл со множеством подписок и
+// импортирует их асинхронно
+// @Summary Bulk import subscriptions
+// @Description Upload a CSV or JSON file of subscriptions (user_id, service_name, price, start_date, end_date) and import them idempotently: a row whose (user_id, service_name) already exists is overwritten rather than rejected. Processing happens in the background; poll the returned job with GET /subscriptions/import/{job_id}.
+// @Tags subscriptions
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV or JSON file of subscriptions"
+// @Success 202 {object} models.ImportJob
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /subscriptions/import [post]
+// @Security BearerAuth
+func (h *SubscriptionHandler) ImportSubscriptions(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context())
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		log.Warnw("Failed to read import file", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "missing file"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		log.Errorw("Failed to open import file", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	defer file.Close()
+
+	rows, rowOffset, err := parseImportFile(fileHeader.Filename, file)
+	if err != nil {
+		log.Warnw("Failed to parse import file", "error", err, "filename", fileHeader.Filename)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	job, err := h.Jobs.Create(c.Request.Context())
+	if err != nil {
+		log.Errorw("Failed to create import job", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	log.Infow("Import job started", "job_id", job.ID, "rows", len(rows))
+	go h.processImport(job.ID, rows, rowOffset, log)
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// GetImportJob возвращает статус и результаты задания импорта
+// @Summary Get an import job
+// @Description Get the status and per-row results of a bulk import job by its UUID.
+// @Tags subscriptions
+// @Produce json
+// @Param job_id path string true "Import Job UUID" Format(uuid)
+// @Success 200 {object} models.ImportJob
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /subscriptions/import/{job_id} [get]
+// @Security BearerAuth
+func (h *SubscriptionHandler) GetImportJob(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context())
+
+	jobID := c.Param("job_id")
+
+	job, err := h.Jobs.Get(c.Request.Context(), jobID)
+	if err != nil {
+		if errors.Is(err, repository.ErrImportJobNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "import job not found"})
+			return
+		}
+		log.Errorw("Database error on import job fetch", "error", err, "job_id", jobID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// processImport upserts each row and records its outcome, then persists the
+// full result set to the job once done. It runs detached from the request
+// (which has already returned 202) so it takes a plain context.Background()
+// and the logger captured before the handler returned, rather than
+// c.Request.Context().
+func (h *SubscriptionHandler) processImport(jobID string, rows []models.ImportRow, rowOffset int, log *zap.SugaredLogger) {
+	ctx := context.Background()
+	results := make([]models.ImportRowResult, 0, len(rows))
+
+	for i, row := range rows {
+		rowNum := i + rowOffset
+		result := models.ImportRowResult{Row: rowNum, UserID: row.UserID, ServiceName: row.ServiceName}
+
+		if row.PriceRaw != "" {
+			price, err := strconv.Atoi(row.PriceRaw)
+			if err != nil {
+				result.Status = models.ImportRowFailed
+				result.Reason = fmt.Sprintf("invalid price %q, expected an integer", row.PriceRaw)
+				results = append(results, result)
+				continue
+			}
+			row.Price = price
+		}
+
+		startDate, err := parseMonthYear(row.StartDate)
+		if err != nil {
+			result.Status = models.ImportRowFailed
+			result.Reason = "invalid start_date format, expected MM-YYYY"
+			results = append(results, result)
+			continue
+		}
+		startDate = toFirstDayOfMonth(startDate)
+
+		var endDatePtr *time.Time
+		if row.EndDate != "" {
+			endDate, err := parseMonthYear(row.EndDate)
+			if err != nil {
+				result.Status = models.ImportRowFailed
+				result.Reason = "invalid end_date format, expected MM-YYYY"
+				results = append(results, result)
+				continue
+			}
+			endDate = toFirstDayOfMonth(endDate)
+			endDatePtr = &endDate
+		}
+
+		// Decide the topic up front: Upsert itself doesn't report whether the
+		// row already existed, and Mutate needs the topic before fn runs.
+		topic := events.TopicSubscriptionCreated
+		if _, err := h.Repo.Get(ctx, row.UserID, row.ServiceName); err == nil {
+			topic = events.TopicSubscriptionUpdated
+		} else if !errors.Is(err, repository.ErrNotFound) {
+			result.Status = models.ImportRowFailed
+			result.Reason = err.Error()
+			results = append(results, result)
+			continue
+		}
+
+		var sub *models.Subscription
+		err = h.Events.Mutate(ctx, topic, func(ctx context.Context, tx *sql.Tx) (events.SubscriptionEvent, error) {
+			var err error
+			sub, err = h.Repo.Upsert(ctx, tx, row.UserID, row.ServiceName, row.Price, startDate, endDatePtr)
+			if err != nil {
+				return events.SubscriptionEvent{}, err
+			}
+			return toEvent(topic, sub), nil
+		})
+		if err != nil {
+			result.Status = models.ImportRowFailed
+			result.Reason = err.Error()
+			results = append(results, result)
+			continue
+		}
+
+		if sub.Version == 1 {
+			result.Status = models.ImportRowCreated
+		} else {
+			result.Status = models.ImportRowUpdated
+		}
+		results = append(results, result)
+	}
+
+	if err := h.Jobs.Complete(ctx, jobID, results); err != nil {
+		log.Errorw("Failed to persist import job results", "error", err, "job_id", jobID)
+		return
+	}
+	log.Infow("Import job finished", "job_id", jobID, "rows", len(results))
+}
+
+// parseImportFile dispatches on the uploaded file's extension: ".csv" or
+// ".json" (anything else is rejected rather than guessed at). The returned
+// rowOffset is the 1-based row number of rows[0], for ImportRowResult.Row:
+// CSV counts its header line as row 1, so data starts at row 2; JSON has no
+// header, so data starts at row 1.
+func parseImportFile(filename string, file multipart.File) (rows []models.ImportRow, rowOffset int, err error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		rows, err = parseImportJSON(file)
+		return rows, 1, err
+	case ".csv":
+		rows, err = parseImportCSV(file)
+		return rows, 2, err
+	default:
+		return nil, 0, fmt.Errorf("unsupported file extension %q, expected .csv or .json", filepath.Ext(filename))
+	}
+}
+
+func parseImportJSON(file io.Reader) ([]models.ImportRow, error) {
+	var rows []models.ImportRow
+	if err := json.NewDecoder(file).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+	return rows, nil
+}
+
+// parseImportCSV reads a CSV file with a header row naming (in any order)
+// the user_id, service_name, price, start_date and optional end_date
+// columns. The price column is read as text and left for processImport to
+// parse, so a bad value fails only its own row instead of the whole upload.
+func parseImportCSV(file io.Reader) ([]models.ImportRow, error) {
+	reader := csv.NewReader(file)
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("could not read CSV header: %v", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, required := range []string{"user_id", "service_name", "price", "start_date"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing required CSV column %q", required)
+		}
+	}
+
+	var rows []models.ImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read CSV row: %v", err)
+		}
+
+		row := models.ImportRow{
+			UserID:      record[columns["user_id"]],
+			ServiceName: record[columns["service_name"]],
+			PriceRaw:    strings.TrimSpace(record[columns["price"]]),
+			StartDate:   record[columns["start_date"]],
+		}
+		if idx, ok := columns["end_date"]; ok {
+			row.EndDate = record[idx]
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"subscription-service/auth"
+	"subscription-service/events"
+	"subscription-service/logger"
+)
+
+// StreamEvents опубликовывает подписчику поток событий жизненного цикла
+// подписок через Server-Sent Events
+// @Summary Stream subscription lifecycle events
+// @Description Open a Server-Sent Events stream of subscription lifecycle events (created/updated/cancelled/renewed/restored), optionally filtered with a tendermint-pubsub-style `key=value AND key=value` query (recognized keys: user_id, service_name). A non-admin caller must filter by their own user_id.
+// @Tags subscriptions
+// @Produce text/event-stream
+// @Param query query string false "Filter query, e.g. user_id=... AND service_name=..."
+// @Success 200 {string} string "text/event-stream"
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /subscriptions/stream [get]
+// @Security BearerAuth
+func (h *SubscriptionHandler) StreamEvents(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context())
+
+	filter, err := events.ParseFilter(c.Query("query"))
+	if err != nil {
+		log.Warnw("Invalid event stream query", "error", err, "query", c.Query("query"))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims, ok := auth.ClaimsFromContext(c.Request.Context())
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	if !claims.IsAdmin && filter.UserID != claims.UserID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "must filter by your own user_id, or be admin"})
+		return
+	}
+
+	sub := h.Hub.Subscribe(filter)
+	defer h.Hub.Unsubscribe(sub)
+
+	log.Infow("Event stream subscriber connected", "user_id", filter.UserID, "service_name", filter.ServiceName)
+
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				return false
+			}
+			c.SSEvent(string(event.Type), event)
+			return true
+		case <-sub.Cancelled():
+			log.Warnw("Event stream subscriber evicted", "error", sub.Err())
+			return false
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
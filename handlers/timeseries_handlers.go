@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"subscription-service/logger"
+	"subscription-service/models"
+	"subscription-service/query"
+)
+
+// GetTimeseries возвращает помесячную разбивку стоимости подписок с
+// опциональным прогнозом
+// @Summary Month-by-month cost breakdown and forecast
+// @Description Returns the total price of matching subscriptions for each calendar month in [start_date, end_date], optionally split by service_name or user_id. With forecast_months and an ungrouped series, appends that many months projecting the trailing 3-month average forward.
+// @Tags analytics
+// @Produce json
+// @Param q query string false "Filter expression, e.g. user_id='...'"
+// @Param start_date query string true "Start month (MM-YYYY)"
+// @Param end_date query string true "End month (MM-YYYY)"
+// @Param group_by query string false "month|service_name|user_id" Enums(month, service_name, user_id)
+// @Param forecast_months query int false "Months to project forward (ungrouped series only)"
+// @Success 200 {array} models.TimeseriesEntry
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /subscriptions/timeseries [get]
+func (h *SubscriptionHandler) GetTimeseries(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context())
+
+	filter, err := query.Parse(c.Query("q"))
+	if err != nil {
+		log.Warnw("Invalid q filter on timeseries", "error", err, "q", c.Query("q"))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	start, err := parseMonthYear(c.Query("start_date"))
+	if err != nil || c.Query("start_date") == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start_date format, expected MM-YYYY"})
+		return
+	}
+	start = toFirstDayOfMonth(start)
+
+	end, err := parseMonthYear(c.Query("end_date"))
+	if err != nil || c.Query("end_date") == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end_date format, expected MM-YYYY"})
+		return
+	}
+	end = toFirstDayOfMonth(end)
+
+	if end.Before(start) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_date must not be before start_date"})
+		return
+	}
+
+	groupBy := c.Query("group_by")
+	if groupBy == "month" {
+		groupBy = ""
+	}
+	if groupBy != "" && groupBy != "service_name" && groupBy != "user_id" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "group_by must be month, service_name or user_id"})
+		return
+	}
+
+	forecastMonths := 0
+	if raw := c.Query("forecast_months"); raw != "" {
+		forecastMonths, err = strconv.Atoi(raw)
+		if err != nil || forecastMonths < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "forecast_months must be a non-negative integer"})
+			return
+		}
+		if forecastMonths > 0 && groupBy != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "forecast_months requires an ungrouped series (group_by=month)"})
+			return
+		}
+	}
+
+	log.Debugw("Calculating timeseries", "q", c.Query("q"), "group_by", groupBy, "forecast_months", forecastMonths)
+
+	buckets, err := h.Repo.Timeseries(c.Request.Context(), filter, start, end, groupBy)
+	if err != nil {
+		log.Errorw("Database error on timeseries", "error", err, "q", c.Query("q"))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	entries := make([]models.TimeseriesEntry, len(buckets))
+	for i, b := range buckets {
+		entries[i] = models.TimeseriesEntry{Month: b.Month, Group: b.Group, Actual: b.Total}
+	}
+
+	if forecastMonths > 0 {
+		entries = append(entries, forecastEntries(entries, end, forecastMonths)...)
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// forecastEntries projects the trailing 3-month average of entries (or
+// fewer, if the series is shorter) forward for the n calendar months after
+// end, as a flat projection.
+func forecastEntries(entries []models.TimeseriesEntry, end time.Time, n int) []models.TimeseriesEntry {
+	window := entries
+	if len(window) > 3 {
+		window = window[len(window)-3:]
+	}
+
+	var sum int
+	for _, e := range window {
+		sum += e.Actual
+	}
+	average := 0
+	if len(window) > 0 {
+		average = sum / len(window)
+	}
+
+	forecast := make([]models.TimeseriesEntry, n)
+	for i := 0; i < n; i++ {
+		month := end.AddDate(0, i+1, 0)
+		forecast[i] = models.TimeseriesEntry{Month: month.Format("01-2006"), Projected: average}
+	}
+	return forecast
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"subscription-service/logger"
+	"subscription-service/repository"
+	"subscription-service/websub"
+)
+
+// Websub implements the W3C WebSub hub endpoint: a third-party system
+// subscribes or unsubscribes a callback URL to notifications for a topic
+// (currently only "/subscriptions/{user_id}" is recognized) by POSTing
+// hub.mode, hub.topic, hub.callback and, for a subscribe, hub.secret and
+// hub.lease_seconds. Per the WebSub spec the hub accepts the request
+// immediately and verifies hub.callback asynchronously, only persisting the
+// subscription once the callback has echoed the challenge back.
+// @Summary WebSub subscribe/unsubscribe
+// @Description Register or remove a WebSub callback for a subscription topic. The challenge verification GET against hub.callback runs asynchronously; poll hub.callback's own state to confirm.
+// @Tags websub
+// @Accept x-www-form-urlencoded
+// @Param hub.mode formData string true "subscribe or unsubscribe"
+// @Param hub.topic formData string true "Topic, e.g. /subscriptions/{user_id}"
+// @Param hub.callback formData string true "Callback URL"
+// @Param hub.secret formData string false "HMAC secret used to sign notification payloads (subscribe only)"
+// @Param hub.lease_seconds formData string false "Subscription lifetime in seconds (subscribe only)"
+// @Success 202
+// @Failure 400 {object} map[string]string
+// @Router /websub [post]
+func (h *SubscriptionHandler) Websub(c *gin.Context) {
+	log := logger.FromContext(c.Request.Context())
+
+	mode := websub.Mode(c.PostForm("hub.mode"))
+	topic := c.PostForm("hub.topic")
+	callback := c.PostForm("hub.callback")
+	secret := c.PostForm("hub.secret")
+
+	if mode != websub.ModeSubscribe && mode != websub.ModeUnsubscribe {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "hub.mode must be subscribe or unsubscribe"})
+		return
+	}
+	if callback == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "hub.callback is required"})
+		return
+	}
+	if websub.UserFromTopic(topic) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "hub.topic must be of the form /subscriptions/{user_id}"})
+		return
+	}
+
+	leaseSeconds, err := websub.ParseLeaseSeconds(c.PostForm("hub.lease_seconds"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Infow("WebSub request accepted, verifying intent", "mode", mode, "topic", topic, "callback", callback)
+	go h.verifyAndPersistWebSub(mode, topic, callback, secret, leaseSeconds, log)
+
+	c.Status(http.StatusAccepted)
+}
+
+// verifyAndPersistWebSub runs detached from the request (which has already
+// returned 202): it performs the challenge GET against callback and only
+// once the callback has proven it controls the URL does it apply the
+// subscribe/unsubscribe to the WebSubRepository.
+func (h *SubscriptionHandler) verifyAndPersistWebSub(mode websub.Mode, topic, callback, secret string, leaseSeconds int, log *zap.SugaredLogger) {
+	ctx := context.Background()
+
+	if err := websub.VerifyIntent(callback, string(mode), topic, leaseSeconds); err != nil {
+		log.Warnw("WebSub intent verification failed", "error", err, "mode", mode, "topic", topic, "callback", callback)
+		return
+	}
+
+	switch mode {
+	case websub.ModeSubscribe:
+		leaseExpiry := time.Now().Add(time.Duration(leaseSeconds) * time.Second)
+		if _, err := h.WebSub.Subscribe(ctx, topic, callback, secret, leaseExpiry); err != nil {
+			log.Errorw("Failed to persist websub subscription", "error", err, "topic", topic, "callback", callback)
+			return
+		}
+	case websub.ModeUnsubscribe:
+		if err := h.WebSub.Unsubscribe(ctx, topic, callback); err != nil && !errors.Is(err, repository.ErrWebSubSubscriptionNotFound) {
+			log.Errorw("Failed to remove websub subscription", "error", err, "topic", topic, "callback", callback)
+			return
+		}
+	}
+
+	log.Infow("WebSub intent verified", "mode", mode, "topic", topic, "callback", callback)
+}
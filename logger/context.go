@@ -0,0 +1,25 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type ctxKey struct{}
+
+// WithContext returns a copy of ctx carrying log, retrievable with
+// FromContext. The request middleware uses this to attach a per-request
+// child logger (request_id, method, path, user_id) to the request context.
+func WithContext(ctx context.Context, log *zap.SugaredLogger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, log)
+}
+
+// FromContext returns the logger attached to ctx by WithContext, or a no-op
+// logger if none was attached (e.g. a context built outside of a request).
+func FromContext(ctx context.Context) *zap.SugaredLogger {
+	if log, ok := ctx.Value(ctxKey{}).(*zap.SugaredLogger); ok {
+		return log
+	}
+	return zap.NewNop().Sugar()
+}
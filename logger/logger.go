@@ -5,33 +5,58 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
-var Log *zap.SugaredLogger
+// Config parametrizes the logger built by New, read from the environment by
+// config.Load (LOG_LEVEL, LOG_FORMAT) alongside the existing DEVELOPMENT flag.
+type Config struct {
+	Development bool
+	Level       string // LOG_LEVEL: debug|info|warn|error|dpanic|panic|fatal, default "debug"
+	Format      string // LOG_FORMAT: console|json, defaults to "console" in development and "json" otherwise
+}
 
-func InitLogger(development bool) {
-	var config zap.Config
+// New строит *zap.SugaredLogger: читаемый цветной вывод для разработки и
+// структурированный JSON для продакшена. Вызывающий код владеет возвращённым
+// логгером и передаёт его коллабораторам через конструкторы, а не через
+// package-level переменную.
+func New(cfg Config) (*zap.SugaredLogger, error) {
+	var zcfg zap.Config
 
-	if development {
-		// Для разработки: читабельный вывод в консоль
-		config = zap.NewDevelopmentConfig()
-		config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	if cfg.Development {
+		zcfg = zap.NewDevelopmentConfig()
 	} else {
-		// Для продакшена: структурированный JSON
-		config = zap.NewProductionConfig()
+		zcfg = zap.NewProductionConfig()
+		// Продакшен: не более 100 сообщений в секунду на уникальное
+		// (уровень, сообщение) сочетание, и по одному из каждых 100 сверх
+		// этого — защита от заполнения логов при массовых сбоях.
+		zcfg.Sampling = &zap.SamplingConfig{Initial: 100, Thereafter: 100}
+	}
+
+	format := cfg.Format
+	if format == "" {
+		if cfg.Development {
+			format = "console"
+		} else {
+			format = "json"
+		}
+	}
+	zcfg.Encoding = format
+	if format == "console" {
+		zcfg.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
 	}
 
-	// Настройка уровня логирования (можно вынести в переменные окружения)
-	config.Level.SetLevel(zapcore.DebugLevel)
+	level := zapcore.DebugLevel
+	if cfg.Level != "" {
+		if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+			return nil, err
+		}
+	}
+	zcfg.Level = zap.NewAtomicLevelAt(level)
 
 	// Строим логгер
-	baseLogger, err := config.Build()
+	baseLogger, err := zcfg.Build()
 	if err != nil {
-		panic(err) // Если логгер не создался, падаем сразу
+		return nil, err
 	}
-	defer baseLogger.Sync() // Важно: сбрасываем буферизованные логи при выходе
 
 	// Создаем SugaredLogger для удобного логирования в формате printf
-	Log = baseLogger.Sugar()
-
-	// Первое информационное сообщение
-	Log.Info("Logger initialized successfully")
+	return baseLogger.Sugar(), nil
 }
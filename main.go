@@ -16,78 +16,43 @@
 package main
 
 import (
-	"github.com/gin-gonic/gin"
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
+	"context"
+	"fmt"
 	"os"
-	"subscription-service/db"
-	_ "subscription-service/docs" // ОЧЕНЬ ВАЖНО: добавьте этот импорт! Путь должен совпадать с вашим модулем.
-	"subscription-service/handlers"
-	"subscription-service/logger"
+	"os/signal"
+	"syscall"
+
+	"subscription-service/app"
+	"subscription-service/config"
 )
 
 func main() {
-	// 1. Инициализация логгера
-	logger.InitLogger(true)
-	defer logger.Log.Sync()
-
-	// 2. Инициализация базы данных
-	logger.Log.Info("Initializing database connection...")
-	db.InitDB()
-	defer db.DB.Close()
-
-	// 3. Миграции и сиды
-	logger.Log.Info("Running database migrations...")
-	err := db.RunMigrations(db.DB)
+	cfg, err := config.Load()
 	if err != nil {
-		logger.Log.Fatalf("Migration failed: %v", err)
+		fmt.Fprintf(os.Stderr, "could not load config: %v\n", err)
+		os.Exit(1)
 	}
 
-	logger.Log.Info("Running database seeds...")
-	err = db.RunSeeds(db.DB)
-	if err != nil {
-		logger.Log.Warnf("Seeds warning: %v", err)
+	// `migrate <cmd>` управляет схемой без запуска HTTP-сервера.
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := app.RunMigrateCommand(cfg, os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
 	}
 
-	// 4. Настройка роутера
-	router := gin.Default()
-
-	url := ginSwagger.URL("/swagger/doc.json") // Указываем URL к нашему swagger.json
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, url))
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
 
-	// Middleware для логирования HTTP-запросов
-	router.Use(gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		logger.Log.Infow("HTTP Request",
-			"status", param.StatusCode,
-			"method", param.Method,
-			"path", param.Path,
-			"latency", param.Latency,
-			"clientIP", param.ClientIP,
-		)
-		return ""
-	}))
-	router.Use(gin.Recovery())
-
-	// Инициализация обработчиков
-	subscriptionHandler := handlers.NewSubscriptionHandler(db.DB)
-
-	// Маршруты для CRUDL операций
-	router.POST("/subscriptions/:user_id/:service_name", subscriptionHandler.CreateSubscription)
-	router.GET("/subscriptions/:user_id/:service_name", subscriptionHandler.GetSubscription)
-	router.PUT("/subscriptions/:user_id/:service_name", subscriptionHandler.UpdateSubscription)
-	router.DELETE("/subscriptions/:user_id/:service_name", subscriptionHandler.DeleteSubscription)
-	router.GET("/subscriptions", subscriptionHandler.ListSubscriptions)
-	router.GET("/subscriptions/total", subscriptionHandler.GetTotalCost)
-
-	// Получаем порт из переменных окружения
-	port := os.Getenv("APP_PORT")
-	if port == "" {
-		port = "8080" // значение по умолчанию
+	a, err := app.New(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not build app: %v\n", err)
+		os.Exit(1)
 	}
 
-	// Запуск сервера
-	logger.Log.Infow("Server is starting", "port", port)
-	if err := router.Run(":" + port); err != nil {
-		logger.Log.Fatalw("Failed to start server", "error", err)
+	if err := a.Run(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "server error: %v\n", err)
+		os.Exit(1)
 	}
 }
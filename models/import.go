@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// ImportRow — одна строка CSV/JSON файла массового импорта подписок.
+type ImportRow struct {
+	UserID      string `json:"user_id" csv:"user_id"`
+	ServiceName string `json:"service_name" csv:"service_name"`
+	Price       int    `json:"price" csv:"price"`
+	// PriceRaw carries the unparsed CSV price column so a bad value is
+	// reported as a per-row ImportRowFailed, same as a bad date; empty for
+	// JSON rows, whose price already decoded as a number into Price.
+	PriceRaw  string `json:"-" csv:"-"`
+	StartDate string `json:"start_date" csv:"start_date"` // Формат "MM-YYYY"
+	EndDate   string `json:"end_date" csv:"end_date"`     // Формат "MM-YYYY"
+}
+
+// ImportRowStatus — результат обработки одной строки импорта.
+type ImportRowStatus string
+
+const (
+	ImportRowCreated ImportRowStatus = "created"
+	ImportRowUpdated ImportRowStatus = "updated"
+	ImportRowFailed  ImportRowStatus = "failed"
+)
+
+// ImportRowResult репортит, что произошло со строкой Row после обработки:
+// подписка создана, обновлена, либо импорт этой строки не удался (Reason
+// объясняет почему). Row is 1-based from the first data row: for CSV that
+// counts the header line as row 1, so data starts at row 2; JSON has no
+// header, so data starts at row 1.
+type ImportRowResult struct {
+	Row         int             `json:"row"`
+	UserID      string          `json:"user_id"`
+	ServiceName string          `json:"service_name"`
+	Status      ImportRowStatus `json:"status"`
+	Reason      string          `json:"reason,omitempty"`
+}
+
+// ImportJobStatus отслеживает прогресс асинхронной обработки файла импорта.
+type ImportJobStatus string
+
+const (
+	ImportJobProcessing ImportJobStatus = "processing"
+	ImportJobCompleted  ImportJobStatus = "completed"
+)
+
+// ImportJob — статус и результаты одного запуска массового импорта,
+// идентифицируемого UUID, который ImportSubscriptions возвращает сразу же,
+// не дожидаясь обработки файла.
+type ImportJob struct {
+	ID        string            `json:"id"`
+	Status    ImportJobStatus   `json:"status"`
+	Results   []ImportRowResult `json:"results"`
+	CreatedAt time.Time         `json:"created_at"`
+}
@@ -1,11 +1,18 @@
 package models
 
+import "time"
+
 type Subscription struct {
 	ServiceName string `json:"service_name"`
 	Price       int    `json:"price"`
 	UserID      string `json:"user_id"`
 	StartDate   string `json:"start_date"`         // Формат "MM-YYYY"
 	EndDate     string `json:"end_date,omitempty"` // Формат "MM-YYYY"
+	Version     int    `json:"version"`            // Увеличивается при каждом изменении, используется событиями жизненного цикла
+	// DeletedAt is set once DeleteSubscription has soft-deleted the row, nil
+	// otherwise. A soft-deleted subscription is excluded from Get/Update/
+	// List unless include_deleted is set, until RestoreSubscription clears it.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 type SubscriptionRequest struct {
@@ -20,13 +27,40 @@ type CompositeKey struct {
 	ServiceName string `uri:"service_name" binding:"required"`
 }
 
-type FilterParams struct {
-	UserID      string `form:"user_id"`
-	ServiceName string `form:"service_name"`
-	StartDate   string `form:"start_date"` // Формат "MM-YYYY"
-	EndDate     string `form:"end_date"`   // Формат "MM-YYYY"
-}
-
 type TotalCostResponse struct {
 	TotalCost int `json:"total_cost"`
 }
+
+// SubscriptionPage is a page of ListSubscriptions results alongside the
+// pagination state needed to fetch the next one.
+type SubscriptionPage struct {
+	Subscriptions []Subscription `json:"subscriptions"`
+	Page          int            `json:"page"`
+	PerPage       int            `json:"per_page"`
+	Total         int            `json:"total"`
+}
+
+// SubscriptionEventOp identifies the kind of mutation a SubscriptionAuditEntry
+// describes.
+type SubscriptionEventOp string
+
+const (
+	SubscriptionEventCreated  SubscriptionEventOp = "create"
+	SubscriptionEventUpdated  SubscriptionEventOp = "update"
+	SubscriptionEventDeleted  SubscriptionEventOp = "delete"
+	SubscriptionEventRestored SubscriptionEventOp = "restore"
+)
+
+// SubscriptionAuditEntry is one row of the subscription_events audit trail,
+// written in the same transaction as the Create/Update/Delete/Restore
+// mutation it describes. Before is nil for a create; for a (soft) delete,
+// After is the row as kept, with DeletedAt set.
+type SubscriptionAuditEntry struct {
+	Op          SubscriptionEventOp `json:"op"`
+	Actor       string              `json:"actor"`
+	UserID      string              `json:"user_id"`
+	ServiceName string              `json:"service_name"`
+	Before      *Subscription       `json:"before,omitempty"`
+	After       *Subscription       `json:"after,omitempty"`
+	At          time.Time           `json:"at"`
+}
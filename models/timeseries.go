@@ -0,0 +1,20 @@
+package models
+
+// TimeseriesBucket is one repository-level data point: the total price of
+// every matching subscription active during Month, optionally split by
+// Group (a service_name or user_id value, when the query was grouped).
+type TimeseriesBucket struct {
+	Month string
+	Group string
+	Total int
+}
+
+// TimeseriesEntry is the API-facing shape of a GetTimeseries response:
+// either a historical bucket (Actual set) or a forecast month appended past
+// the requested range (Projected set).
+type TimeseriesEntry struct {
+	Month     string `json:"month"`
+	Group     string `json:"group,omitempty"`
+	Actual    int    `json:"actual,omitempty"`
+	Projected int    `json:"projected,omitempty"`
+}
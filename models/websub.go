@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// WebSubSubscription is a third-party callback registered via POST /websub
+// (W3C WebSub) to receive notifications of changes to Topic (e.g.
+// "/subscriptions/{user_id}"). It is keyed by the (Topic, Callback) tuple,
+// so re-subscribing the same callback to the same topic refreshes its
+// Secret and LeaseExpiry in place rather than creating a duplicate row.
+type WebSubSubscription struct {
+	Topic       string    `json:"topic"`
+	Callback    string    `json:"callback"`
+	Secret      string    `json:"-"`
+	LeaseExpiry time.Time `json:"lease_expiry"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,96 @@
+package query
+
+import "time"
+
+// Op is a comparison operator parsed from a `q=` filter expression.
+type Op string
+
+const (
+	OpEq  Op = "="
+	OpNeq Op = "!="
+	OpLt  Op = "<"
+	OpLte Op = "<="
+	OpGt  Op = ">"
+	OpGte Op = ">="
+)
+
+// Expr is a node of the AST Parse produces from a `q=` filter expression.
+// The grammar only joins terms with AND (see And), so every Expr tree is a
+// flat, left-deep chain of leaf predicates — Flatten walks it back into that
+// list.
+type Expr interface{ isExpr() }
+
+// And is a conjunction of two expressions.
+type And struct {
+	Left  Expr
+	Right Expr
+}
+
+// Comparison is a `column op value` predicate against an allow-listed
+// column. Value is a string for user_id/service_name, an int for price.
+type Comparison struct {
+	Column string
+	Op     Op
+	Value  interface{}
+}
+
+// In is a `column IN ('a', 'b', ...)` predicate against an allow-listed
+// column.
+type In struct {
+	Column string
+	Values []string
+}
+
+// ActiveOn matches subscriptions active during Month: started on or before
+// it, and either open-ended or not yet ended.
+type ActiveOn struct {
+	Month time.Time
+}
+
+// ActiveBetween matches subscriptions active at any point in [From, To].
+type ActiveBetween struct {
+	From time.Time
+	To   time.Time
+}
+
+// EndsBefore matches subscriptions with an end_date set and earlier than
+// Month.
+type EndsBefore struct {
+	Month time.Time
+}
+
+func (And) isExpr()           {}
+func (Comparison) isExpr()    {}
+func (In) isExpr()            {}
+func (ActiveOn) isExpr()      {}
+func (ActiveBetween) isExpr() {}
+func (EndsBefore) isExpr()    {}
+
+// Flatten walks e's AND chain back into its leaf predicates, in the order
+// they were written.
+func Flatten(e Expr) []Expr {
+	if e == nil {
+		return nil
+	}
+	and, ok := e.(And)
+	if !ok {
+		return []Expr{e}
+	}
+	return append(Flatten(and.Left), Flatten(and.Right)...)
+}
+
+// RequiredUserID reports the value of a top-level `user_id='...'` equality
+// leaf in e, if any. Middleware uses it to confirm a non-admin caller has
+// scoped their filter to their own user_id.
+func RequiredUserID(e Expr) (string, bool) {
+	for _, leaf := range Flatten(e) {
+		cmp, ok := leaf.(Comparison)
+		if !ok || cmp.Column != "user_id" || cmp.Op != OpEq {
+			continue
+		}
+		if userID, ok := cmp.Value.(string); ok {
+			return userID, true
+		}
+	}
+	return "", false
+}
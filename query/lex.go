@@ -0,0 +1,121 @@
+package query
+
+import "fmt"
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+	tokAnd
+	tokIn
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+func isAlpha(c byte) bool { return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') }
+
+// lex tokenizes a `q=` filter expression. Strings are single-quoted with no
+// escaping, matching the simple examples the expression language is meant
+// to cover.
+func lex(input string) ([]token, error) {
+	var tokens []token
+
+	i, n := 0, len(input)
+	for i < n {
+		c := input[i]
+
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{tokLParen, "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{tokRParen, ")"})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{tokComma, ","})
+			i++
+		case c == '\'':
+			j := i + 1
+			for j < n && input[j] != '\'' {
+				j++
+			}
+			if j >= n {
+				return nil, fmt.Errorf("unterminated string literal")
+			}
+			tokens = append(tokens, token{tokString, input[i+1 : j]})
+			i = j + 1
+		case c == '=':
+			tokens = append(tokens, token{tokOp, "="})
+			i++
+		case c == '!' && i+1 < n && input[i+1] == '=':
+			tokens = append(tokens, token{tokOp, "!="})
+			i += 2
+		case c == '<' && i+1 < n && input[i+1] == '=':
+			tokens = append(tokens, token{tokOp, "<="})
+			i += 2
+		case c == '<':
+			tokens = append(tokens, token{tokOp, "<"})
+			i++
+		case c == '>' && i+1 < n && input[i+1] == '=':
+			tokens = append(tokens, token{tokOp, ">="})
+			i += 2
+		case c == '>':
+			tokens = append(tokens, token{tokOp, ">"})
+			i++
+		case isDigit(c):
+			j := i + 1
+			for j < n && isDigit(input[j]) {
+				j++
+			}
+			tokens = append(tokens, token{tokNumber, input[i:j]})
+			i = j
+		case isAlpha(c) || c == '_':
+			j := i + 1
+			for j < n && (isAlpha(input[j]) || isDigit(input[j]) || input[j] == '_') {
+				j++
+			}
+			word := input[i:j]
+			switch upper(word) {
+			case "AND":
+				tokens = append(tokens, token{tokAnd, word})
+			case "IN":
+				tokens = append(tokens, token{tokIn, word})
+			default:
+				tokens = append(tokens, token{tokIdent, word})
+			}
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected character %q", string(c))
+		}
+	}
+
+	tokens = append(tokens, token{tokEOF, ""})
+	return tokens, nil
+}
+
+// upper uppercases ASCII letters only — enough for the AND/IN keywords,
+// without pulling in strings.ToUpper's unicode handling.
+func upper(s string) string {
+	out := make([]byte, len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 'a' && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		out[i] = c
+	}
+	return string(out)
+}
@@ -0,0 +1,134 @@
+package query
+
+import (
+	"fmt"
+	"time"
+
+	"subscription-service/models"
+)
+
+// Match reports whether sub satisfies e. A nil e matches everything — the
+// memory repository's equivalent of an empty WHERE clause.
+func Match(e Expr, sub models.Subscription) (bool, error) {
+	if e == nil {
+		return true, nil
+	}
+
+	switch v := e.(type) {
+	case And:
+		left, err := Match(v.Left, sub)
+		if err != nil || !left {
+			return false, err
+		}
+		return Match(v.Right, sub)
+
+	case Comparison:
+		return matchComparison(v, sub)
+
+	case In:
+		if v.Column != "user_id" && v.Column != "service_name" {
+			return false, fmt.Errorf("IN is not supported on column %q", v.Column)
+		}
+		field := sub.UserID
+		if v.Column == "service_name" {
+			field = sub.ServiceName
+		}
+		for _, want := range v.Values {
+			if field == want {
+				return true, nil
+			}
+		}
+		return false, nil
+
+	case ActiveOn:
+		return activeBetween(sub, v.Month, v.Month)
+
+	case ActiveBetween:
+		return activeBetween(sub, v.From, v.To)
+
+	case EndsBefore:
+		if sub.EndDate == "" {
+			return false, nil
+		}
+		end, err := time.Parse("01-2006", sub.EndDate)
+		if err != nil {
+			return false, err
+		}
+		return end.Before(v.Month), nil
+
+	default:
+		return false, fmt.Errorf("unsupported expression %T", e)
+	}
+}
+
+func matchComparison(c Comparison, sub models.Subscription) (bool, error) {
+	switch c.Column {
+	case "user_id":
+		return compareStrings(c.Op, sub.UserID, c.Value)
+	case "service_name":
+		return compareStrings(c.Op, sub.ServiceName, c.Value)
+	case "price":
+		want, ok := c.Value.(int)
+		if !ok {
+			return false, fmt.Errorf("price comparison expects an int, got %T", c.Value)
+		}
+		return compareInts(c.Op, sub.Price, want)
+	default:
+		return false, fmt.Errorf("unsupported column %q", c.Column)
+	}
+}
+
+func compareStrings(op Op, got string, value interface{}) (bool, error) {
+	want, ok := value.(string)
+	if !ok {
+		return false, fmt.Errorf("comparison expects a string, got %T", value)
+	}
+	switch op {
+	case OpEq:
+		return got == want, nil
+	case OpNeq:
+		return got != want, nil
+	default:
+		return false, fmt.Errorf("operator %q is not supported on string columns", op)
+	}
+}
+
+func compareInts(op Op, got, want int) (bool, error) {
+	switch op {
+	case OpEq:
+		return got == want, nil
+	case OpNeq:
+		return got != want, nil
+	case OpLt:
+		return got < want, nil
+	case OpLte:
+		return got <= want, nil
+	case OpGt:
+		return got > want, nil
+	case OpGte:
+		return got >= want, nil
+	default:
+		return false, fmt.Errorf("unsupported operator %q", op)
+	}
+}
+
+// activeBetween reports whether sub was active at any point in [from, to]:
+// it started on or before to, and either never ends or didn't end before
+// from.
+func activeBetween(sub models.Subscription, from, to time.Time) (bool, error) {
+	start, err := time.Parse("01-2006", sub.StartDate)
+	if err != nil {
+		return false, err
+	}
+	if start.After(to) {
+		return false, nil
+	}
+	if sub.EndDate == "" {
+		return true, nil
+	}
+	end, err := time.Parse("01-2006", sub.EndDate)
+	if err != nil {
+		return false, err
+	}
+	return !end.Before(from), nil
+}
@@ -0,0 +1,102 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Pagination defaults and bounds for ListSubscriptions.
+const (
+	DefaultPage    = 1
+	DefaultPerPage = 20
+	MaxPerPage     = 100
+)
+
+// ParsePage parses the page/per_page query parameters, defaulting to
+// DefaultPage/DefaultPerPage when empty and clamping per_page to
+// MaxPerPage.
+func ParsePage(pageParam, perPageParam string) (page, perPage int, err error) {
+	page = DefaultPage
+	if pageParam != "" {
+		page, err = strconv.Atoi(pageParam)
+		if err != nil || page < 1 {
+			return 0, 0, fmt.Errorf("invalid page %q", pageParam)
+		}
+	}
+
+	perPage = DefaultPerPage
+	if perPageParam != "" {
+		perPage, err = strconv.Atoi(perPageParam)
+		if err != nil || perPage < 1 {
+			return 0, 0, fmt.Errorf("invalid per_page %q", perPageParam)
+		}
+	}
+	if perPage > MaxPerPage {
+		perPage = MaxPerPage
+	}
+
+	return page, perPage, nil
+}
+
+// orderableColumns are the subscriptions columns order_by may sort on.
+var orderableColumns = map[string]bool{
+	"user_id":      true,
+	"service_name": true,
+	"price":        true,
+	"start_date":   true,
+	"end_date":     true,
+	"version":      true,
+}
+
+// OrderBy is a parsed `order_by=column [asc|desc]` parameter. The zero value
+// means "leave the result in whatever order the backend returns it in".
+type OrderBy struct {
+	Column string
+	Desc   bool
+}
+
+// ParseOrderBy parses "column" or "column asc|desc" against the
+// allow-listed orderableColumns. An empty value returns the zero OrderBy.
+func ParseOrderBy(value string) (OrderBy, error) {
+	if value == "" {
+		return OrderBy{}, nil
+	}
+
+	fields := strings.Fields(value)
+	if len(fields) == 0 || len(fields) > 2 {
+		return OrderBy{}, fmt.Errorf("invalid order_by %q", value)
+	}
+
+	column := strings.ToLower(fields[0])
+	if !orderableColumns[column] {
+		return OrderBy{}, fmt.Errorf("unknown or disallowed order_by column %q", column)
+	}
+
+	desc := false
+	if len(fields) == 2 {
+		switch strings.ToLower(fields[1]) {
+		case "desc":
+			desc = true
+		case "asc":
+			desc = false
+		default:
+			return OrderBy{}, fmt.Errorf("order_by direction must be asc or desc, got %q", fields[1])
+		}
+	}
+
+	return OrderBy{Column: column, Desc: desc}, nil
+}
+
+// SQL renders o as an " ORDER BY column ASC|DESC" clause (with leading
+// space), or "" for the zero value.
+func (o OrderBy) SQL() string {
+	if o.Column == "" {
+		return ""
+	}
+	dir := "ASC"
+	if o.Desc {
+		dir = "DESC"
+	}
+	return fmt.Sprintf(" ORDER BY %s %s", o.Column, dir)
+}
@@ -0,0 +1,208 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// allowedColumns are the subscriptions columns a `q=` expression may filter
+// on. Anything else is rejected rather than passed through to SQL.
+var allowedColumns = map[string]bool{
+	"user_id":      true,
+	"service_name": true,
+	"price":        true,
+}
+
+// parseMonthYear parses the "MM-YYYY" format used throughout the API for
+// subscription dates.
+func parseMonthYear(value string) (time.Time, error) {
+	return time.Parse("01-2006", value)
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	p.pos++
+	return t
+}
+
+func (p *parser) expect(kind tokenKind) (token, error) {
+	t := p.peek()
+	if t.kind != kind {
+		return token{}, fmt.Errorf("unexpected token %q", t.value)
+	}
+	return p.next(), nil
+}
+
+// Parse parses a `q=` filter expression, e.g.
+// `user_id='...' AND service_name IN ('Netflix','Spotify') AND active_on='07-2025' AND price>=500`.
+// An empty input returns a nil Expr, which every Match/ToSQL caller treats
+// as "match everything".
+func Parse(input string) (Expr, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, nil
+	}
+
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	expr, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokEOF {
+		return nil, fmt.Errorf("unexpected trailing input near %q", p.peek().value)
+	}
+
+	return expr, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokAnd {
+		p.next()
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = And{Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseTerm() (Expr, error) {
+	colTok, err := p.expect(tokIdent)
+	if err != nil {
+		return nil, fmt.Errorf("expected a column name: %v", err)
+	}
+	column := strings.ToLower(colTok.value)
+
+	switch column {
+	case "active_on":
+		month, err := p.parseTemporalValue()
+		if err != nil {
+			return nil, fmt.Errorf("invalid active_on: %v", err)
+		}
+		return ActiveOn{Month: month}, nil
+	case "ends_before":
+		month, err := p.parseTemporalValue()
+		if err != nil {
+			return nil, fmt.Errorf("invalid ends_before: %v", err)
+		}
+		return EndsBefore{Month: month}, nil
+	case "active_between":
+		if _, err := p.expect(tokOp); err != nil {
+			return nil, fmt.Errorf("invalid active_between: %v", err)
+		}
+		val, err := p.expect(tokString)
+		if err != nil {
+			return nil, fmt.Errorf("invalid active_between: %v", err)
+		}
+		parts := strings.SplitN(val.value, "..", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid active_between %q: expected MM-YYYY..MM-YYYY", val.value)
+		}
+		from, err := parseMonthYear(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid active_between start %q: expected MM-YYYY", parts[0])
+		}
+		to, err := parseMonthYear(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid active_between end %q: expected MM-YYYY", parts[1])
+		}
+		return ActiveBetween{From: from, To: to}, nil
+	}
+
+	if !allowedColumns[column] {
+		return nil, fmt.Errorf("unknown or disallowed column %q", column)
+	}
+
+	if p.peek().kind == tokIn {
+		p.next()
+		if _, err := p.expect(tokLParen); err != nil {
+			return nil, fmt.Errorf("expected '(' after IN: %v", err)
+		}
+
+		var values []string
+		for {
+			v, err := p.expect(tokString)
+			if err != nil {
+				return nil, fmt.Errorf("expected a quoted value in IN list: %v", err)
+			}
+			values = append(values, v.value)
+
+			if p.peek().kind == tokComma {
+				p.next()
+				continue
+			}
+			break
+		}
+
+		if _, err := p.expect(tokRParen); err != nil {
+			return nil, fmt.Errorf("expected ')' to close IN list: %v", err)
+		}
+		return In{Column: column, Values: values}, nil
+	}
+
+	opTok, err := p.expect(tokOp)
+	if err != nil {
+		return nil, fmt.Errorf("expected an operator after %q: %v", column, err)
+	}
+	op := Op(opTok.value)
+
+	if column == "price" {
+		numTok, err := p.expect(tokNumber)
+		if err != nil {
+			return nil, fmt.Errorf("price requires a numeric value: %v", err)
+		}
+		n, err := strconv.Atoi(numTok.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price %q", numTok.value)
+		}
+		return Comparison{Column: column, Op: op, Value: n}, nil
+	}
+
+	if op != OpEq && op != OpNeq {
+		return nil, fmt.Errorf("column %q only supports = and !=", column)
+	}
+	strTok, err := p.expect(tokString)
+	if err != nil {
+		return nil, fmt.Errorf("%q requires a quoted value: %v", column, err)
+	}
+	return Comparison{Column: column, Op: op, Value: strTok.value}, nil
+}
+
+// parseTemporalValue consumes "=" followed by a quoted "MM-YYYY" value, as
+// used by active_on and ends_before.
+func (p *parser) parseTemporalValue() (time.Time, error) {
+	opTok, err := p.expect(tokOp)
+	if err != nil || opTok.value != "=" {
+		return time.Time{}, fmt.Errorf("expected '='")
+	}
+	val, err := p.expect(tokString)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected a quoted MM-YYYY value: %v", err)
+	}
+	month, err := parseMonthYear(val.value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not in MM-YYYY format", val.value)
+	}
+	return month, nil
+}
@@ -0,0 +1,97 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect selects the placeholder style ToSQL renders, matching the two SQL
+// backends (Postgres' $N vs SQLite's ?).
+type Dialect int
+
+const (
+	Postgres Dialect = iota
+	SQLite
+)
+
+// Placeholder renders the n-th (1-indexed) bind placeholder in d's style.
+// Repositories use it directly to append LIMIT/OFFSET placeholders after a
+// ToSQL-rendered WHERE clause, continuing the same positional numbering.
+func (d Dialect) Placeholder(n int) string {
+	if d == Postgres {
+		return fmt.Sprintf("$%d", n)
+	}
+	return "?"
+}
+
+// ToSQL renders e as a parameterized SQL boolean expression (no leading
+// "WHERE") plus its positional args, in dialect's placeholder style. e may
+// be nil, in which case ToSQL returns an empty clause and no args. Every
+// value is passed as a bind parameter — never concatenated into the query
+// string — and every column name comes from the allow-listed set Parse
+// already validated.
+func ToSQL(e Expr, dialect Dialect) (string, []interface{}, error) {
+	return ToSQLOffset(e, dialect, 0)
+}
+
+// ToSQLOffset is ToSQL for a query that already has offset earlier bind
+// parameters (e.g. a timeseries query's month-range bounds), so the clause's
+// own placeholders continue the numbering instead of restarting at 1.
+func ToSQLOffset(e Expr, dialect Dialect, offset int) (string, []interface{}, error) {
+	if e == nil {
+		return "", nil, nil
+	}
+
+	args := make([]interface{}, offset)
+	clause, err := render(e, dialect, &args)
+	if err != nil {
+		return "", nil, err
+	}
+	return clause, args[offset:], nil
+}
+
+func render(e Expr, d Dialect, args *[]interface{}) (string, error) {
+	switch v := e.(type) {
+	case And:
+		left, err := render(v.Left, d, args)
+		if err != nil {
+			return "", err
+		}
+		right, err := render(v.Right, d, args)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("(%s AND %s)", left, right), nil
+
+	case Comparison:
+		*args = append(*args, v.Value)
+		return fmt.Sprintf("%s %s %s", v.Column, v.Op, d.Placeholder(len(*args))), nil
+
+	case In:
+		placeholders := make([]string, len(v.Values))
+		for i, val := range v.Values {
+			*args = append(*args, val)
+			placeholders[i] = d.Placeholder(len(*args))
+		}
+		return fmt.Sprintf("%s IN (%s)", v.Column, strings.Join(placeholders, ", ")), nil
+
+	case ActiveOn:
+		*args = append(*args, v.Month, v.Month)
+		return fmt.Sprintf("(start_date <= %s AND (end_date IS NULL OR end_date >= %s))",
+			d.Placeholder(len(*args)-1), d.Placeholder(len(*args))), nil
+
+	case ActiveBetween:
+		// Overlap test: the subscription started on or before the window's
+		// end and (is open-ended or) hadn't already ended before it began.
+		*args = append(*args, v.To, v.From)
+		return fmt.Sprintf("(start_date <= %s AND (end_date IS NULL OR end_date >= %s))",
+			d.Placeholder(len(*args)-1), d.Placeholder(len(*args))), nil
+
+	case EndsBefore:
+		*args = append(*args, v.Month)
+		return fmt.Sprintf("(end_date IS NOT NULL AND end_date < %s)", d.Placeholder(len(*args))), nil
+
+	default:
+		return "", fmt.Errorf("unsupported expression %T", e)
+	}
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// TokenBlacklistRepository records revoked JWTs by jti (logout, refresh
+// rotation) so AuthRequired can reject a token that hasn't expired yet but
+// has been explicitly invalidated.
+type TokenBlacklistRepository interface {
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"subscription-service/models"
+)
+
+// ErrImportJobNotFound сигнализирует об отсутствии задания импорта с
+// заданным ID; GetImportJob транслирует её в HTTP 404.
+var ErrImportJobNotFound = errors.New("import job not found")
+
+// ImportJobRepository хранит статус и результаты асинхронной обработки
+// массового импорта подписок, чтобы ImportSubscriptions мог вернуть UUID
+// задания немедленно, а GetImportJob — опрашивать его прогресс позже.
+type ImportJobRepository interface {
+	Create(ctx context.Context) (*models.ImportJob, error)
+	Complete(ctx context.Context, id string, results []models.ImportRowResult) error
+	Get(ctx context.Context, id string) (*models.ImportJob, error)
+}
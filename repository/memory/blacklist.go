@@ -0,0 +1,32 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TokenBlacklistRepository is a thread-safe, in-process
+// repository.TokenBlacklistRepository implementation for unit tests.
+type TokenBlacklistRepository struct {
+	mu      sync.RWMutex
+	revoked map[string]time.Time // jti -> expires_at
+}
+
+func NewTokenBlacklistRepository() *TokenBlacklistRepository {
+	return &TokenBlacklistRepository{revoked: make(map[string]time.Time)}
+}
+
+func (r *TokenBlacklistRepository) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.revoked[jti] = expiresAt
+	return nil
+}
+
+func (r *TokenBlacklistRepository) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.revoked[jti]
+	return ok, nil
+}
@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"subscription-service/models"
+	"subscription-service/repository"
+)
+
+// ImportJobRepository is a thread-safe, in-process
+// repository.ImportJobRepository implementation for unit tests, keyed by job
+// UUID.
+type ImportJobRepository struct {
+	mu   sync.RWMutex
+	jobs map[string]models.ImportJob
+}
+
+func NewImportJobRepository() *ImportJobRepository {
+	return &ImportJobRepository{jobs: make(map[string]models.ImportJob)}
+}
+
+func (r *ImportJobRepository) Create(ctx context.Context) (*models.ImportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job := models.ImportJob{
+		ID:        uuid.NewString(),
+		Status:    models.ImportJobProcessing,
+		Results:   []models.ImportRowResult{},
+		CreatedAt: time.Now(),
+	}
+	r.jobs[job.ID] = job
+
+	out := job
+	return &out, nil
+}
+
+func (r *ImportJobRepository) Complete(ctx context.Context, id string, results []models.ImportRowResult) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job, ok := r.jobs[id]
+	if !ok {
+		return repository.ErrImportJobNotFound
+	}
+	job.Status = models.ImportJobCompleted
+	job.Results = results
+	r.jobs[id] = job
+	return nil
+}
+
+func (r *ImportJobRepository) Get(ctx context.Context, id string) (*models.ImportJob, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, repository.ErrImportJobNotFound
+	}
+	out := job
+	return &out, nil
+}
@@ -0,0 +1,347 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"subscription-service/models"
+	"subscription-service/query"
+	"subscription-service/repository"
+)
+
+// SubscriptionRepository is a thread-safe, in-process
+// repository.SubscriptionRepository implementation for unit tests, keyed by
+// models.CompositeKey. It never touches a database, so BeginTx is a no-op:
+// it always hands Create/Update/Delete a nil tx, which they simply ignore.
+type SubscriptionRepository struct {
+	mu     sync.RWMutex
+	subs   map[models.CompositeKey]models.Subscription
+	events map[models.CompositeKey][]models.SubscriptionAuditEntry
+}
+
+func NewSubscriptionRepository() *SubscriptionRepository {
+	return &SubscriptionRepository{
+		subs:   make(map[models.CompositeKey]models.Subscription),
+		events: make(map[models.CompositeKey][]models.SubscriptionAuditEntry),
+	}
+}
+
+func (r *SubscriptionRepository) BeginTx(ctx context.Context) (*sql.Tx, error) {
+	return nil, nil
+}
+
+func (r *SubscriptionRepository) Create(ctx context.Context, tx *sql.Tx, actor, userID, serviceName string, price int, startDate time.Time, endDate *time.Time) (*models.Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sub := models.Subscription{
+		ServiceName: serviceName,
+		Price:       price,
+		UserID:      userID,
+		StartDate:   startDate.Format("01-2006"),
+		Version:     1,
+	}
+	if endDate != nil {
+		sub.EndDate = endDate.Format("01-2006")
+	}
+
+	key := models.CompositeKey{UserID: userID, ServiceName: serviceName}
+	r.subs[key] = sub
+	out := sub
+	r.recordEvent(key, models.SubscriptionEventCreated, actor, nil, &out)
+	return &out, nil
+}
+
+func (r *SubscriptionRepository) Update(ctx context.Context, tx *sql.Tx, actor, userID, serviceName string, price int, startDate time.Time, endDate *time.Time) (*models.Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := models.CompositeKey{UserID: userID, ServiceName: serviceName}
+	existing, ok := r.subs[key]
+	if !ok || existing.DeletedAt != nil {
+		return nil, repository.ErrNotFound
+	}
+	before := existing
+
+	existing.Price = price
+	existing.StartDate = startDate.Format("01-2006")
+	existing.EndDate = ""
+	if endDate != nil {
+		existing.EndDate = endDate.Format("01-2006")
+	}
+	existing.Version++
+
+	r.subs[key] = existing
+	out := existing
+	r.recordEvent(key, models.SubscriptionEventUpdated, actor, &before, &out)
+	return &out, nil
+}
+
+func (r *SubscriptionRepository) Upsert(ctx context.Context, tx *sql.Tx, userID, serviceName string, price int, startDate time.Time, endDate *time.Time) (*models.Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := models.CompositeKey{UserID: userID, ServiceName: serviceName}
+	sub, exists := r.subs[key]
+	sub.ServiceName = serviceName
+	sub.UserID = userID
+	sub.Price = price
+	sub.StartDate = startDate.Format("01-2006")
+	sub.EndDate = ""
+	if endDate != nil {
+		sub.EndDate = endDate.Format("01-2006")
+	}
+	if exists {
+		sub.Version++
+	} else {
+		sub.Version = 1
+	}
+
+	r.subs[key] = sub
+	out := sub
+	return &out, nil
+}
+
+func (r *SubscriptionRepository) Get(ctx context.Context, userID, serviceName string) (*models.Subscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sub, ok := r.subs[models.CompositeKey{UserID: userID, ServiceName: serviceName}]
+	if !ok || sub.DeletedAt != nil {
+		return nil, repository.ErrNotFound
+	}
+	out := sub
+	return &out, nil
+}
+
+func (r *SubscriptionRepository) Delete(ctx context.Context, tx *sql.Tx, actor, userID, serviceName string) (*models.Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := models.CompositeKey{UserID: userID, ServiceName: serviceName}
+	sub, ok := r.subs[key]
+	if !ok || sub.DeletedAt != nil {
+		return nil, repository.ErrNotFound
+	}
+	before := sub
+
+	now := time.Now()
+	sub.DeletedAt = &now
+	sub.Version++
+	r.subs[key] = sub
+
+	out := sub
+	r.recordEvent(key, models.SubscriptionEventDeleted, actor, &before, &out)
+	return &out, nil
+}
+
+func (r *SubscriptionRepository) Restore(ctx context.Context, tx *sql.Tx, actor, userID, serviceName string) (*models.Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := models.CompositeKey{UserID: userID, ServiceName: serviceName}
+	sub, ok := r.subs[key]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	if sub.DeletedAt == nil {
+		return nil, repository.ErrNotDeleted
+	}
+	before := sub
+
+	sub.DeletedAt = nil
+	sub.Version++
+	r.subs[key] = sub
+
+	out := sub
+	r.recordEvent(key, models.SubscriptionEventRestored, actor, &before, &out)
+	return &out, nil
+}
+
+func (r *SubscriptionRepository) History(ctx context.Context, userID, serviceName string) ([]models.SubscriptionAuditEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := r.events[models.CompositeKey{UserID: userID, ServiceName: serviceName}]
+	out := make([]models.SubscriptionAuditEntry, len(entries))
+	copy(out, entries)
+	return out, nil
+}
+
+// recordEvent appends an audit entry for key. Callers already hold r.mu.
+func (r *SubscriptionRepository) recordEvent(key models.CompositeKey, op models.SubscriptionEventOp, actor string, before, after *models.Subscription) {
+	r.events[key] = append(r.events[key], models.SubscriptionAuditEntry{
+		Op:          op,
+		Actor:       actor,
+		UserID:      key.UserID,
+		ServiceName: key.ServiceName,
+		Before:      before,
+		After:       after,
+		At:          time.Now(),
+	})
+}
+
+func (r *SubscriptionRepository) List(ctx context.Context, filter query.Expr, order query.OrderBy, page, perPage int, includeDeleted bool) ([]models.Subscription, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matched []models.Subscription
+	for _, sub := range r.subs {
+		if sub.DeletedAt != nil && !includeDeleted {
+			continue
+		}
+		ok, err := query.Match(filter, sub)
+		if err != nil {
+			return nil, 0, err
+		}
+		if ok {
+			matched = append(matched, sub)
+		}
+	}
+
+	sortSubscriptions(matched, order)
+
+	total := len(matched)
+	start := (page - 1) * perPage
+	if start > total {
+		start = total
+	}
+	end := start + perPage
+	if end > total {
+		end = total
+	}
+
+	return matched[start:end], total, nil
+}
+
+func (r *SubscriptionRepository) TotalCost(ctx context.Context, filter query.Expr) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var total int
+	for _, sub := range r.subs {
+		if sub.DeletedAt != nil {
+			continue
+		}
+		ok, err := query.Match(filter, sub)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			total += sub.Price
+		}
+	}
+	return total, nil
+}
+
+func (r *SubscriptionRepository) Timeseries(ctx context.Context, filter query.Expr, start, end time.Time, groupBy string) ([]models.TimeseriesBucket, error) {
+	if groupBy != "" && groupBy != "service_name" && groupBy != "user_id" {
+		return nil, fmt.Errorf("unsupported group_by %q", groupBy)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matched []models.Subscription
+	for _, sub := range r.subs {
+		if sub.DeletedAt != nil {
+			continue
+		}
+		ok, err := query.Match(filter, sub)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matched = append(matched, sub)
+		}
+	}
+
+	var buckets []models.TimeseriesBucket
+	for month := start; !month.After(end); month = month.AddDate(0, 1, 0) {
+		totals := make(map[string]int)
+		var groups []string
+
+		for _, sub := range matched {
+			active, err := query.Match(query.ActiveOn{Month: month}, sub)
+			if err != nil {
+				return nil, err
+			}
+			if !active {
+				continue
+			}
+
+			group := ""
+			if groupBy == "service_name" {
+				group = sub.ServiceName
+			} else if groupBy == "user_id" {
+				group = sub.UserID
+			}
+			if _, seen := totals[group]; !seen {
+				groups = append(groups, group)
+			}
+			totals[group] += sub.Price
+		}
+
+		if groupBy == "" {
+			buckets = append(buckets, models.TimeseriesBucket{Month: month.Format("01-2006"), Total: totals[""]})
+			continue
+		}
+
+		sort.Strings(groups)
+		for _, group := range groups {
+			buckets = append(buckets, models.TimeseriesBucket{Month: month.Format("01-2006"), Group: group, Total: totals[group]})
+		}
+	}
+
+	return buckets, nil
+}
+
+// sortSubscriptions sorts subs in place by order, leaving them in map
+// iteration order (arbitrary) when order is the zero value.
+func sortSubscriptions(subs []models.Subscription, order query.OrderBy) {
+	if order.Column == "" {
+		return
+	}
+	sort.SliceStable(subs, func(i, j int) bool {
+		if order.Desc {
+			i, j = j, i
+		}
+		return lessSubscription(subs[i], subs[j], order.Column)
+	})
+}
+
+func lessSubscription(a, b models.Subscription, column string) bool {
+	switch column {
+	case "user_id":
+		return a.UserID < b.UserID
+	case "service_name":
+		return a.ServiceName < b.ServiceName
+	case "price":
+		return a.Price < b.Price
+	case "version":
+		return a.Version < b.Version
+	case "start_date":
+		aDate, _ := time.Parse("01-2006", a.StartDate)
+		bDate, _ := time.Parse("01-2006", b.StartDate)
+		return aDate.Before(bDate)
+	case "end_date":
+		aDate, bDate := farFuture, farFuture
+		if a.EndDate != "" {
+			aDate, _ = time.Parse("01-2006", a.EndDate)
+		}
+		if b.EndDate != "" {
+			bDate, _ = time.Parse("01-2006", b.EndDate)
+		}
+		return aDate.Before(bDate)
+	default:
+		return false
+	}
+}
+
+// farFuture stands in for "never ends" when sorting by end_date, so
+// open-ended subscriptions sort after any subscription with a fixed end.
+var farFuture = time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
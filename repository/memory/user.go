@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"subscription-service/models"
+	"subscription-service/repository"
+)
+
+// UserRepository is a thread-safe, in-process repository.UserRepository
+// implementation for unit tests.
+type UserRepository struct {
+	mu         sync.RWMutex
+	byID       map[string]models.User
+	emailIndex map[string]string // email -> id
+}
+
+func NewUserRepository() *UserRepository {
+	return &UserRepository{
+		byID:       make(map[string]models.User),
+		emailIndex: make(map[string]string),
+	}
+}
+
+func (r *UserRepository) Create(ctx context.Context, email, passwordHash string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, taken := r.emailIndex[email]; taken {
+		return nil, repository.ErrEmailTaken
+	}
+
+	user := models.User{ID: uuid.NewString(), Email: email, PasswordHash: passwordHash}
+	r.byID[user.ID] = user
+	r.emailIndex[email] = user.ID
+
+	out := user
+	return &out, nil
+}
+
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.emailIndex[email]
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+	user := r.byID[id]
+	return &user, nil
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.byID[id]
+	if !ok {
+		return nil, repository.ErrUserNotFound
+	}
+	return &user, nil
+}
@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"subscription-service/models"
+	"subscription-service/repository"
+)
+
+type websubKey struct {
+	topic    string
+	callback string
+}
+
+// WebSubRepository is a thread-safe, in-process repository.WebSubRepository
+// implementation for unit tests, keyed by (topic, callback).
+type WebSubRepository struct {
+	mu   sync.RWMutex
+	subs map[websubKey]models.WebSubSubscription
+}
+
+func NewWebSubRepository() *WebSubRepository {
+	return &WebSubRepository{subs: make(map[websubKey]models.WebSubSubscription)}
+}
+
+func (r *WebSubRepository) Subscribe(ctx context.Context, topic, callback, secret string, leaseExpiry time.Time) (*models.WebSubSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := websubKey{topic: topic, callback: callback}
+	sub, ok := r.subs[key]
+	if !ok {
+		sub.CreatedAt = time.Now()
+	}
+	sub.Topic = topic
+	sub.Callback = callback
+	sub.Secret = secret
+	sub.LeaseExpiry = leaseExpiry
+	r.subs[key] = sub
+
+	out := sub
+	return &out, nil
+}
+
+func (r *WebSubRepository) Unsubscribe(ctx context.Context, topic, callback string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := websubKey{topic: topic, callback: callback}
+	if _, ok := r.subs[key]; !ok {
+		return repository.ErrWebSubSubscriptionNotFound
+	}
+	delete(r.subs, key)
+	return nil
+}
+
+func (r *WebSubRepository) ListByTopic(ctx context.Context, topic string) ([]models.WebSubSubscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	var subs []models.WebSubSubscription
+	for _, sub := range r.subs {
+		if sub.Topic == topic && sub.LeaseExpiry.After(now) {
+			subs = append(subs, sub)
+		}
+	}
+	return subs, nil
+}
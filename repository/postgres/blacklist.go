@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// TokenBlacklistRepository is the repository.TokenBlacklistRepository
+// implementation backed by the token_blacklist table.
+type TokenBlacklistRepository struct {
+	db *sql.DB
+}
+
+func NewTokenBlacklistRepository(db *sql.DB) *TokenBlacklistRepository {
+	return &TokenBlacklistRepository{db: db}
+}
+
+func (r *TokenBlacklistRepository) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	query := `
+		INSERT INTO token_blacklist (jti, expires_at)
+		VALUES ($1, $2)
+		ON CONFLICT (jti) DO NOTHING
+	`
+	_, err := r.db.ExecContext(ctx, query, jti, expiresAt)
+	return err
+}
+
+func (r *TokenBlacklistRepository) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM token_blacklist WHERE jti = $1)`
+	var revoked bool
+	err := r.db.QueryRowContext(ctx, query, jti).Scan(&revoked)
+	return revoked, err
+}
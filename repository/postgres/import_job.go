@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"subscription-service/models"
+	"subscription-service/repository"
+)
+
+// ImportJobRepository is the repository.ImportJobRepository implementation
+// backed by the import_jobs table.
+type ImportJobRepository struct {
+	db *sql.DB
+}
+
+func NewImportJobRepository(db *sql.DB) *ImportJobRepository {
+	return &ImportJobRepository{db: db}
+}
+
+func (r *ImportJobRepository) Create(ctx context.Context) (*models.ImportJob, error) {
+	query := `
+		INSERT INTO import_jobs (id, status, results, created_at)
+		VALUES ($1, $2, '[]', now())
+		RETURNING id, status, results, created_at
+	`
+	return scanImportJob(r.db.QueryRowContext(ctx, query, uuid.NewString(), models.ImportJobProcessing))
+}
+
+func (r *ImportJobRepository) Complete(ctx context.Context, id string, results []models.ImportRowResult) error {
+	payload, err := json.Marshal(results)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE import_jobs SET status = $1, results = $2 WHERE id = $3`
+	res, err := r.db.ExecContext(ctx, query, models.ImportJobCompleted, payload, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repository.ErrImportJobNotFound
+	}
+	return nil
+}
+
+func (r *ImportJobRepository) Get(ctx context.Context, id string) (*models.ImportJob, error) {
+	query := `SELECT id, status, results, created_at FROM import_jobs WHERE id = $1`
+	return scanImportJob(r.db.QueryRowContext(ctx, query, id))
+}
+
+func scanImportJob(row scanner) (*models.ImportJob, error) {
+	var job models.ImportJob
+	var status string
+	var payload []byte
+
+	err := row.Scan(&job.ID, &status, &payload, &job.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrImportJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	job.Status = models.ImportJobStatus(status)
+	if err := json.Unmarshal(payload, &job.Results); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"subscription-service/models"
+	"subscription-service/repository"
+)
+
+// UserRepository is the repository.UserRepository implementation backed by
+// the users table.
+type UserRepository struct {
+	db *sql.DB
+}
+
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+func (r *UserRepository) Create(ctx context.Context, email, passwordHash string) (*models.User, error) {
+	query := `
+		INSERT INTO users (id, email, password_hash)
+		VALUES ($1, $2, $3)
+		RETURNING id, email, password_hash, is_admin
+	`
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, uuid.NewString(), email, passwordHash))
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, repository.ErrEmailTaken
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	query := `SELECT id, email, password_hash, is_admin FROM users WHERE email = $1`
+	return scanUser(r.db.QueryRowContext(ctx, query, email))
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
+	query := `SELECT id, email, password_hash, is_admin FROM users WHERE id = $1`
+	return scanUser(r.db.QueryRowContext(ctx, query, id))
+}
+
+func scanUser(row scanner) (*models.User, error) {
+	var user models.User
+	err := row.Scan(&user.ID, &user.Email, &user.PasswordHash, &user.IsAdmin)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"subscription-service/models"
+	"subscription-service/repository"
+)
+
+// WebSubRepository is the repository.WebSubRepository implementation backed
+// by the websub_subscriptions table.
+type WebSubRepository struct {
+	db *sql.DB
+}
+
+func NewWebSubRepository(db *sql.DB) *WebSubRepository {
+	return &WebSubRepository{db: db}
+}
+
+func (r *WebSubRepository) Subscribe(ctx context.Context, topic, callback, secret string, leaseExpiry time.Time) (*models.WebSubSubscription, error) {
+	query := `
+		INSERT INTO websub_subscriptions (topic, callback, secret, lease_expiry, created_at)
+		VALUES ($1, $2, $3, $4, now())
+		ON CONFLICT (topic, callback) DO UPDATE
+			SET secret = EXCLUDED.secret, lease_expiry = EXCLUDED.lease_expiry
+		RETURNING topic, callback, secret, lease_expiry, created_at
+	`
+	return scanWebSubSubscription(r.db.QueryRowContext(ctx, query, topic, callback, secret, leaseExpiry))
+}
+
+func (r *WebSubRepository) Unsubscribe(ctx context.Context, topic, callback string) error {
+	query := `DELETE FROM websub_subscriptions WHERE topic = $1 AND callback = $2`
+	res, err := r.db.ExecContext(ctx, query, topic, callback)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repository.ErrWebSubSubscriptionNotFound
+	}
+	return nil
+}
+
+func (r *WebSubRepository) ListByTopic(ctx context.Context, topic string) ([]models.WebSubSubscription, error) {
+	query := `
+		SELECT topic, callback, secret, lease_expiry, created_at
+		FROM websub_subscriptions
+		WHERE topic = $1 AND lease_expiry > now()
+	`
+	rows, err := r.db.QueryContext(ctx, query, topic)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subs []models.WebSubSubscription
+	for rows.Next() {
+		sub, err := scanWebSubSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, *sub)
+	}
+	return subs, rows.Err()
+}
+
+func scanWebSubSubscription(row scanner) (*models.WebSubSubscription, error) {
+	var sub models.WebSubSubscription
+	err := row.Scan(&sub.Topic, &sub.Callback, &sub.Secret, &sub.LeaseExpiry, &sub.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrWebSubSubscriptionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
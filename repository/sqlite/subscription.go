@@ -0,0 +1,478 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"subscription-service/models"
+	"subscription-service/query"
+	"subscription-service/repository"
+)
+
+// SubscriptionRepository is the repository.SubscriptionRepository
+// implementation backed by a SQLite subscriptions table, for local dev and
+// integration tests without a Postgres instance.
+type SubscriptionRepository struct {
+	db *sql.DB
+}
+
+func NewSubscriptionRepository(db *sql.DB) *SubscriptionRepository {
+	return &SubscriptionRepository{db: db}
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx, letting every mutation
+// run either directly or inside an outbox transaction without duplicating
+// the query.
+type execer interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func (r *SubscriptionRepository) BeginTx(ctx context.Context) (*sql.Tx, error) {
+	return r.db.BeginTx(ctx, nil)
+}
+
+func (r *SubscriptionRepository) exec(tx *sql.Tx) execer {
+	if tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+// withTx runs fn against tx if the caller already supplied one (the Bus'
+// outbox transaction), or opens and commits/rolls back its own otherwise.
+// Every mutation needs a transaction of its own even without an outbox,
+// since it always writes a subscription_events audit row alongside the
+// subscriptions change and the two must commit together.
+func (r *SubscriptionRepository) withTx(ctx context.Context, tx *sql.Tx, fn func(tx *sql.Tx) error) error {
+	if tx != nil {
+		return fn(tx)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *SubscriptionRepository) Create(ctx context.Context, tx *sql.Tx, actor, userID, serviceName string, price int, startDate time.Time, endDate *time.Time) (*models.Subscription, error) {
+	var sub *models.Subscription
+	err := r.withTx(ctx, tx, func(tx *sql.Tx) error {
+		query := `
+			INSERT INTO subscriptions (service_name, price, user_id, start_date, end_date)
+			VALUES (?, ?, ?, ?, ?)
+			RETURNING service_name, price, user_id, start_date, end_date, version, deleted_at
+		`
+		var err error
+		sub, err = scanSubscription(tx.QueryRowContext(ctx, query, serviceName, price, userID, startDate, endDate))
+		if err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, models.SubscriptionEventCreated, actor, nil, sub)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (r *SubscriptionRepository) Update(ctx context.Context, tx *sql.Tx, actor, userID, serviceName string, price int, startDate time.Time, endDate *time.Time) (*models.Subscription, error) {
+	var sub *models.Subscription
+	err := r.withTx(ctx, tx, func(tx *sql.Tx) error {
+		before, err := getForUpdate(ctx, tx, userID, serviceName)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+
+		query := `
+			UPDATE subscriptions
+			SET price = ?, start_date = ?, end_date = ?, version = version + 1
+			WHERE user_id = ? AND service_name = ? AND deleted_at IS NULL
+			RETURNING service_name, price, user_id, start_date, end_date, version, deleted_at
+		`
+		sub, err = scanSubscription(tx.QueryRowContext(ctx, query, price, startDate, endDate, userID, serviceName))
+		if err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, models.SubscriptionEventUpdated, actor, before, sub)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (r *SubscriptionRepository) Upsert(ctx context.Context, tx *sql.Tx, userID, serviceName string, price int, startDate time.Time, endDate *time.Time) (*models.Subscription, error) {
+	query := `
+		INSERT INTO subscriptions (service_name, price, user_id, start_date, end_date)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (user_id, service_name) DO UPDATE
+		SET price = excluded.price, start_date = excluded.start_date, end_date = excluded.end_date, version = subscriptions.version + 1
+		RETURNING service_name, price, user_id, start_date, end_date, version, deleted_at
+	`
+	return scanSubscription(r.exec(tx).QueryRowContext(ctx, query, serviceName, price, userID, startDate, endDate))
+}
+
+func (r *SubscriptionRepository) Get(ctx context.Context, userID, serviceName string) (*models.Subscription, error) {
+	query := `
+		SELECT service_name, price, user_id, start_date, end_date, version, deleted_at
+		FROM subscriptions
+		WHERE user_id = ? AND service_name = ? AND deleted_at IS NULL
+	`
+	return scanSubscription(r.db.QueryRowContext(ctx, query, userID, serviceName))
+}
+
+func (r *SubscriptionRepository) Delete(ctx context.Context, tx *sql.Tx, actor, userID, serviceName string) (*models.Subscription, error) {
+	var sub *models.Subscription
+	err := r.withTx(ctx, tx, func(tx *sql.Tx) error {
+		before, err := getForUpdate(ctx, tx, userID, serviceName)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+
+		query := `
+			UPDATE subscriptions
+			SET deleted_at = CURRENT_TIMESTAMP, version = version + 1
+			WHERE user_id = ? AND service_name = ? AND deleted_at IS NULL
+			RETURNING service_name, price, user_id, start_date, end_date, version, deleted_at
+		`
+		sub, err = scanSubscription(tx.QueryRowContext(ctx, query, userID, serviceName))
+		if err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, models.SubscriptionEventDeleted, actor, before, sub)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (r *SubscriptionRepository) Restore(ctx context.Context, tx *sql.Tx, actor, userID, serviceName string) (*models.Subscription, error) {
+	var sub *models.Subscription
+	err := r.withTx(ctx, tx, func(tx *sql.Tx) error {
+		before, err := getForUpdate(ctx, tx, userID, serviceName)
+		if err != nil {
+			return err
+		}
+		if before.DeletedAt == nil {
+			return repository.ErrNotDeleted
+		}
+
+		query := `
+			UPDATE subscriptions
+			SET deleted_at = NULL, version = version + 1
+			WHERE user_id = ? AND service_name = ? AND deleted_at IS NOT NULL
+			RETURNING service_name, price, user_id, start_date, end_date, version, deleted_at
+		`
+		sub, err = scanSubscription(tx.QueryRowContext(ctx, query, userID, serviceName))
+		if err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, models.SubscriptionEventRestored, actor, before, sub)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (r *SubscriptionRepository) History(ctx context.Context, userID, serviceName string) ([]models.SubscriptionAuditEntry, error) {
+	query := `
+		SELECT op, actor, before_json, after_json, at
+		FROM subscription_events
+		WHERE user_id = ? AND service_name = ?
+		ORDER BY at ASC, id ASC
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.SubscriptionAuditEntry
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows, userID, serviceName)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+func (r *SubscriptionRepository) List(ctx context.Context, filter query.Expr, order query.OrderBy, page, perPage int, includeDeleted bool) ([]models.Subscription, int, error) {
+	where, args, err := query.ToSQL(filter, query.SQLite)
+	if err != nil {
+		return nil, 0, err
+	}
+	where = withDeletedClause(where, includeDeleted)
+
+	var total int
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM subscriptions %s", where)
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	listArgs := append(append([]interface{}{}, args...), perPage, (page-1)*perPage)
+	listQuery := fmt.Sprintf(`
+		SELECT service_name, price, user_id, start_date, end_date, version, deleted_at
+		FROM subscriptions
+		%s%s
+		LIMIT ? OFFSET ?
+	`, where, order.SQL())
+
+	rows, err := r.db.QueryContext(ctx, listQuery, listArgs...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var subscriptions []models.Subscription
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		subscriptions = append(subscriptions, *sub)
+	}
+
+	return subscriptions, total, rows.Err()
+}
+
+func (r *SubscriptionRepository) TotalCost(ctx context.Context, filter query.Expr) (int, error) {
+	where, args, err := query.ToSQL(filter, query.SQLite)
+	if err != nil {
+		return 0, err
+	}
+	where = withDeletedClause(where, false)
+
+	sqlQuery := fmt.Sprintf("SELECT COALESCE(SUM(price), 0) FROM subscriptions %s", where)
+
+	var total int
+	err = r.db.QueryRowContext(ctx, sqlQuery, args...).Scan(&total)
+	return total, err
+}
+
+// Timeseries has no SQLite equivalent of Postgres' generate_series, so it
+// walks the month range in Go and issues one query per month instead of a
+// single set-based query. That's fine for this backend's actual purpose —
+// local dev and integration tests — rather than production analytics.
+func (r *SubscriptionRepository) Timeseries(ctx context.Context, filter query.Expr, start, end time.Time, groupBy string) ([]models.TimeseriesBucket, error) {
+	groupCol, err := timeseriesGroupColumn(groupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	where, filterArgs, err := query.ToSQLOffset(filter, query.SQLite, 2)
+	if err != nil {
+		return nil, err
+	}
+	filterClause := " AND deleted_at IS NULL"
+	if where != "" {
+		filterClause += " AND " + where
+	}
+
+	var buckets []models.TimeseriesBucket
+	for month := start; !month.After(end); month = month.AddDate(0, 1, 0) {
+		args := append([]interface{}{month, month}, filterArgs...)
+
+		if groupCol == "" {
+			sqlQuery := fmt.Sprintf(`
+				SELECT COALESCE(SUM(price), 0)
+				FROM subscriptions
+				WHERE start_date <= ? AND (end_date >= ? OR end_date IS NULL)%s
+			`, filterClause)
+
+			var total int
+			if err := r.db.QueryRowContext(ctx, sqlQuery, args...).Scan(&total); err != nil {
+				return nil, err
+			}
+			buckets = append(buckets, models.TimeseriesBucket{Month: month.Format("01-2006"), Total: total})
+			continue
+		}
+
+		sqlQuery := fmt.Sprintf(`
+			SELECT %s, COALESCE(SUM(price), 0)
+			FROM subscriptions
+			WHERE start_date <= ? AND (end_date >= ? OR end_date IS NULL)%s
+			GROUP BY %s
+			ORDER BY %s
+		`, groupCol, filterClause, groupCol, groupCol)
+
+		rows, err := r.db.QueryContext(ctx, sqlQuery, args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var b models.TimeseriesBucket
+			if err := rows.Scan(&b.Group, &b.Total); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			b.Month = month.Format("01-2006")
+			buckets = append(buckets, b)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return buckets, nil
+}
+
+// timeseriesGroupColumn allow-lists the group_by column Timeseries may
+// interpolate into the query, mirroring how query.Parse allow-lists filter
+// columns.
+func timeseriesGroupColumn(groupBy string) (string, error) {
+	switch groupBy {
+	case "":
+		return "", nil
+	case "service_name", "user_id":
+		return groupBy, nil
+	default:
+		return "", fmt.Errorf("unsupported group_by %q", groupBy)
+	}
+}
+
+// withDeletedClause turns a ToSQL where-clause (no leading "WHERE") into a
+// full WHERE clause that also excludes soft-deleted rows, unless
+// includeDeleted is set.
+func withDeletedClause(where string, includeDeleted bool) string {
+	clauses := []string{}
+	if !includeDeleted {
+		clauses = append(clauses, "deleted_at IS NULL")
+	}
+	if where != "" {
+		clauses = append(clauses, where)
+	}
+	if len(clauses) == 0 {
+		return ""
+	}
+	out := "WHERE "
+	for i, c := range clauses {
+		if i > 0 {
+			out += " AND "
+		}
+		out += c
+	}
+	return out
+}
+
+// scanner is satisfied by both *sql.Row and *sql.Rows so Create/Update/Get
+// and List can share the same scan logic.
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSubscription(row scanner) (*models.Subscription, error) {
+	var sub models.Subscription
+	var startDate time.Time
+	var endDate sql.NullTime
+	var deletedAt sql.NullTime
+
+	err := row.Scan(&sub.ServiceName, &sub.Price, &sub.UserID, &startDate, &endDate, &sub.Version, &deletedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repository.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sub.StartDate = startDate.Format("01-2006")
+	if endDate.Valid {
+		sub.EndDate = endDate.Time.Format("01-2006")
+	}
+	if deletedAt.Valid {
+		sub.DeletedAt = &deletedAt.Time
+	}
+
+	return &sub, nil
+}
+
+// getForUpdate returns the subscription at (userID, serviceName), including
+// a soft-deleted one, so it can be recorded as a SubscriptionAuditEntry's
+// Before ahead of the mutation within tx.
+func getForUpdate(ctx context.Context, tx *sql.Tx, userID, serviceName string) (*models.Subscription, error) {
+	query := `
+		SELECT service_name, price, user_id, start_date, end_date, version, deleted_at
+		FROM subscriptions
+		WHERE user_id = ? AND service_name = ?
+	`
+	return scanSubscription(tx.QueryRowContext(ctx, query, userID, serviceName))
+}
+
+// recordEvent writes a subscription_events audit row inside tx, describing
+// op as performed by actor against before/after (either may be nil, per
+// models.SubscriptionAuditEntry).
+func recordEvent(ctx context.Context, tx *sql.Tx, op models.SubscriptionEventOp, actor string, before, after *models.Subscription) error {
+	userID, serviceName := "", ""
+	switch {
+	case after != nil:
+		userID, serviceName = after.UserID, after.ServiceName
+	case before != nil:
+		userID, serviceName = before.UserID, before.ServiceName
+	}
+
+	var beforeJSON, afterJSON []byte
+	var err error
+	if before != nil {
+		if beforeJSON, err = json.Marshal(before); err != nil {
+			return err
+		}
+	}
+	if after != nil {
+		if afterJSON, err = json.Marshal(after); err != nil {
+			return err
+		}
+	}
+
+	query := `
+		INSERT INTO subscription_events (user_id, service_name, op, actor, before_json, after_json)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	_, err = tx.ExecContext(ctx, query, userID, serviceName, op, actor, beforeJSON, afterJSON)
+	return err
+}
+
+func scanAuditEntry(row scanner, userID, serviceName string) (models.SubscriptionAuditEntry, error) {
+	var entry models.SubscriptionAuditEntry
+	var op, actor string
+	var beforeJSON, afterJSON []byte
+
+	if err := row.Scan(&op, &actor, &beforeJSON, &afterJSON, &entry.At); err != nil {
+		return models.SubscriptionAuditEntry{}, err
+	}
+
+	entry.Op = models.SubscriptionEventOp(op)
+	entry.Actor = actor
+	entry.UserID = userID
+	entry.ServiceName = serviceName
+
+	if len(beforeJSON) > 0 {
+		entry.Before = &models.Subscription{}
+		if err := json.Unmarshal(beforeJSON, entry.Before); err != nil {
+			return models.SubscriptionAuditEntry{}, err
+		}
+	}
+	if len(afterJSON) > 0 {
+		entry.After = &models.Subscription{}
+		if err := json.Unmarshal(afterJSON, entry.After); err != nil {
+			return models.SubscriptionAuditEntry{}, err
+		}
+	}
+
+	return entry, nil
+}
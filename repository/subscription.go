@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"subscription-service/models"
+	"subscription-service/query"
+)
+
+// ErrNotFound сигнализирует об отсутствии подписки с заданным составным
+// ключом; обработчики транслируют её в HTTP 404.
+var ErrNotFound = errors.New("subscription not found")
+
+// ErrNotDeleted сигнализирует, что RestoreSubscription вызван для подписки,
+// которая существует, но не была удалена; обработчики транслируют её в
+// HTTP 409.
+var ErrNotDeleted = errors.New("subscription is not deleted")
+
+// SubscriptionRepository инкапсулирует доступ к данным подписок, позволяя
+// SubscriptionHandler не зависеть от конкретного хранилища.
+//
+// Create/Update/Delete принимают опциональный *sql.Tx: если он не nil,
+// мутация выполняется внутри уже открытой транзакции (так события
+// публикуются в той же транзакции, что и сама мутация — transactional
+// outbox), иначе SQL-репозитории открывают собственную транзакцию для
+// мутации и её записи аудита.
+type SubscriptionRepository interface {
+	BeginTx(ctx context.Context) (*sql.Tx, error)
+	// Create, Update, Delete and Restore each write a SubscriptionAuditEntry
+	// to subscription_events in the same transaction as the mutation itself,
+	// attributed to actor (the caller's user ID).
+	Create(ctx context.Context, tx *sql.Tx, actor, userID, serviceName string, price int, startDate time.Time, endDate *time.Time) (*models.Subscription, error)
+	Update(ctx context.Context, tx *sql.Tx, actor, userID, serviceName string, price int, startDate time.Time, endDate *time.Time) (*models.Subscription, error)
+	// Upsert creates the subscription identified by (userID, serviceName) if
+	// it does not exist yet, or overwrites it in place otherwise. Used by the
+	// bulk import endpoint, where rows are not known in advance to be new or
+	// existing. The returned Subscription's Version is 1 exactly when the row
+	// was created, letting the caller tell the two cases apart without a
+	// separate return value.
+	Upsert(ctx context.Context, tx *sql.Tx, userID, serviceName string, price int, startDate time.Time, endDate *time.Time) (*models.Subscription, error)
+	// Get returns the active (non soft-deleted) subscription at the given
+	// key, ErrNotFound if it doesn't exist or has been soft-deleted.
+	Get(ctx context.Context, userID, serviceName string) (*models.Subscription, error)
+	// Delete soft-deletes the subscription: it sets deleted_at rather than
+	// removing the row, so Restore and History keep working against it.
+	// Returns ErrNotFound if it doesn't exist or is already deleted.
+	Delete(ctx context.Context, tx *sql.Tx, actor, userID, serviceName string) (*models.Subscription, error)
+	// Restore reverses a soft delete, clearing deleted_at. Returns
+	// ErrNotFound if no such subscription exists at all, ErrNotDeleted if it
+	// exists but isn't currently deleted.
+	Restore(ctx context.Context, tx *sql.Tx, actor, userID, serviceName string) (*models.Subscription, error)
+	// History returns every audit entry recorded for (userID, serviceName)
+	// across Create/Update/Delete/Restore, oldest first.
+	History(ctx context.Context, userID, serviceName string) ([]models.SubscriptionAuditEntry, error)
+	// List returns the page of subscriptions matching filter (nil matches
+	// everything), ordered by order (the zero value leaves backend-native
+	// order), along with the total number of matching rows across all pages.
+	// Soft-deleted subscriptions are excluded unless includeDeleted is set.
+	List(ctx context.Context, filter query.Expr, order query.OrderBy, page, perPage int, includeDeleted bool) ([]models.Subscription, int, error)
+	// TotalCost sums the price of every subscription matching filter. Date
+	// scoping is expressed in filter itself, via active_on/active_between.
+	// Soft-deleted subscriptions never count.
+	TotalCost(ctx context.Context, filter query.Expr) (int, error)
+	// Timeseries returns one bucket per calendar month in [start, end]
+	// (inclusive), summing the price of every filter-matching subscription
+	// active that month. groupBy is "", "service_name" or "user_id": empty
+	// returns a single bucket per month; either column name splits each
+	// month's total further by that column's distinct values (months with
+	// no matching subscription are omitted rather than returned as zero).
+	// Soft-deleted subscriptions never count.
+	Timeseries(ctx context.Context, filter query.Expr, start, end time.Time, groupBy string) ([]models.TimeseriesBucket, error)
+}
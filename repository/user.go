@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"subscription-service/models"
+)
+
+// ErrUserNotFound сигнализирует об отсутствии пользователя с заданным email/id.
+var ErrUserNotFound = errors.New("user not found")
+
+// ErrEmailTaken сигнализирует о попытке зарегистрироваться на уже занятый email.
+var ErrEmailTaken = errors.New("email already registered")
+
+// UserRepository инкапсулирует доступ к данным пользователей для пакета auth.
+type UserRepository interface {
+	Create(ctx context.Context, email, passwordHash string) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	GetByID(ctx context.Context, id string) (*models.User, error)
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"subscription-service/models"
+)
+
+// ErrWebSubSubscriptionNotFound сигнализирует об отсутствии WebSub-подписки
+// с заданными topic/callback; при отписке обработчик Websub считает такую
+// отписку уже выполненной и не логирует её как ошибку.
+var ErrWebSubSubscriptionNotFound = errors.New("websub subscription not found")
+
+// WebSubRepository хранит WebSub-подписки внешних систем на уведомления об
+// изменении topic, ключ — пара (topic, callback).
+type WebSubRepository interface {
+	// Subscribe upserts the subscription for (topic, callback): a repeat
+	// subscribe request refreshes secret and leaseExpiry in place instead of
+	// creating a duplicate row.
+	Subscribe(ctx context.Context, topic, callback, secret string, leaseExpiry time.Time) (*models.WebSubSubscription, error)
+	Unsubscribe(ctx context.Context, topic, callback string) error
+	// ListByTopic returns every non-expired subscription for topic, for the
+	// notifier to fan a published event out to.
+	ListByTopic(ctx context.Context, topic string) ([]models.WebSubSubscription, error)
+}
@@ -0,0 +1,147 @@
+package websub
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"subscription-service/events"
+	"subscription-service/models"
+	"subscription-service/repository"
+)
+
+// deliveryTimeout bounds a single notification POST attempt.
+const deliveryTimeout = 5 * time.Second
+
+// maxDeliveryAttempts is how many times in total (the first try plus its
+// retries) Notifier POSTs a notification before giving up on that callback
+// for this event.
+const maxDeliveryAttempts = 3
+
+// retryBaseDelay is the delay before the first retry, doubled on each
+// subsequent attempt (1s, 2s).
+const retryBaseDelay = 1 * time.Second
+
+// Notifier subscribes to the in-process events.Hub and, for every
+// subscription lifecycle event, dispatches a signed notification POST to
+// every WebSub callback registered on the event's topic.
+type Notifier struct {
+	repo   repository.WebSubRepository
+	client *http.Client
+	log    *zap.SugaredLogger
+}
+
+func NewNotifier(repo repository.WebSubRepository, log *zap.SugaredLogger) *Notifier {
+	return &Notifier{repo: repo, client: &http.Client{Timeout: deliveryTimeout}, log: log}
+}
+
+// Run subscribes to hub and dispatches notifications until ctx is
+// cancelled. It is meant to run in its own goroutine for the life of the
+// application, the same way app.Run runs the Watermill event router.
+func (n *Notifier) Run(ctx context.Context, hub *events.Hub) {
+	sub := hub.Subscribe(events.Filter{})
+	defer hub.Unsubscribe(sub)
+
+	for {
+		select {
+		case event := <-sub.Events():
+			n.notify(ctx, event)
+		case <-sub.Cancelled():
+			n.log.Errorw("WebSub notifier evicted from event hub", "error", sub.Err())
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// notify looks up every callback registered on event's topic and hands each
+// off to its own delivery attempt, so one slow or dead callback can't delay
+// the others.
+func (n *Notifier) notify(ctx context.Context, event events.SubscriptionEvent) {
+	topic := TopicForUser(event.UserID)
+
+	subs, err := n.repo.ListByTopic(ctx, topic)
+	if err != nil {
+		n.log.Errorw("Could not list websub subscribers", "error", err, "topic", topic)
+		return
+	}
+	if len(subs) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		n.log.Errorw("Could not marshal websub notification payload", "error", err, "topic", topic)
+		return
+	}
+
+	for _, sub := range subs {
+		go n.deliver(ctx, sub, payload)
+	}
+}
+
+// deliver POSTs payload to sub.Callback, signed per the WebSub spec
+// (X-Hub-Signature: sha256=<hex hmac>), retrying with exponential backoff.
+// It runs in its own goroutine, detached from the event that triggered it.
+func (n *Notifier) deliver(ctx context.Context, sub models.WebSubSubscription, payload []byte) {
+	signature := sign(sub.Secret, payload)
+
+	delay := retryBaseDelay
+	for attempt := 1; attempt <= maxDeliveryAttempts; attempt++ {
+		if err := n.post(ctx, sub.Callback, signature, payload); err == nil {
+			return
+		} else if attempt == maxDeliveryAttempts {
+			n.log.Errorw("WebSub delivery exhausted retries", "error", err, "callback", sub.Callback, "topic", sub.Topic)
+			return
+		} else {
+			n.log.Warnw("WebSub delivery attempt failed, retrying", "error", err, "callback", sub.Callback, "topic", sub.Topic, "attempt", attempt)
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+		delay *= 2
+	}
+}
+
+func (n *Notifier) post(ctx context.Context, callback, signature string, payload []byte) error {
+	reqCtx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, callback, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("could not build notification request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Hub-Signature", signature)
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("callback returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// sign computes the WebSub X-Hub-Signature header value: the algorithm name
+// followed by the hex HMAC-SHA256 digest of payload keyed by secret.
+func sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,45 @@
+package websub
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Mode is the `hub.mode` a subscriber sends to POST /websub.
+type Mode string
+
+const (
+	ModeSubscribe   Mode = "subscribe"
+	ModeUnsubscribe Mode = "unsubscribe"
+)
+
+// Lease bounds, in seconds: a subscription lasts a day by default and may
+// not be renewed for longer than 30 days at a time, mirroring the defaults
+// most public WebSub hubs advertise.
+const (
+	DefaultLeaseSeconds = 24 * 60 * 60
+	MinLeaseSeconds     = 60
+	MaxLeaseSeconds     = 30 * 24 * 60 * 60
+)
+
+// ParseLeaseSeconds parses hub.lease_seconds, defaulting to
+// DefaultLeaseSeconds when empty and clamping to [MinLeaseSeconds,
+// MaxLeaseSeconds].
+func ParseLeaseSeconds(value string) (int, error) {
+	if value == "" {
+		return DefaultLeaseSeconds, nil
+	}
+
+	seconds, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hub.lease_seconds %q", value)
+	}
+
+	if seconds < MinLeaseSeconds {
+		seconds = MinLeaseSeconds
+	}
+	if seconds > MaxLeaseSeconds {
+		seconds = MaxLeaseSeconds
+	}
+	return seconds, nil
+}
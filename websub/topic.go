@@ -0,0 +1,22 @@
+package websub
+
+import "strings"
+
+const userTopicPrefix = "/subscriptions/"
+
+// TopicForUser returns the WebSub topic naming user's subscription
+// resource, e.g. "/subscriptions/11111111-1111-1111-1111-111111111111" —
+// the `hub.topic` callers pass to POST /websub to be notified of that
+// user's subscription lifecycle events.
+func TopicForUser(userID string) string {
+	return userTopicPrefix + userID
+}
+
+// UserFromTopic extracts the user_id from a topic built by TopicForUser, or
+// "" if topic isn't of that shape.
+func UserFromTopic(topic string) string {
+	if !strings.HasPrefix(topic, userTopicPrefix) || topic == userTopicPrefix {
+		return ""
+	}
+	return strings.TrimPrefix(topic, userTopicPrefix)
+}
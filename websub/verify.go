@@ -0,0 +1,70 @@
+package websub
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// verifyTimeout bounds how long VerifyIntent waits for the callback to echo
+// the challenge back.
+const verifyTimeout = 5 * time.Second
+
+var verifyClient = &http.Client{Timeout: verifyTimeout}
+
+// VerifyIntent performs the WebSub subscriber-intent verification GET
+// against callback: a fresh hub.challenge plus hub.mode/hub.topic (and
+// hub.lease_seconds for a subscribe), requiring the response body to echo
+// the challenge back exactly. This confirms the caller actually controls
+// callback before a subscription is persisted or removed.
+func VerifyIntent(callback, mode, topic string, leaseSeconds int) error {
+	challenge := uuid.NewString()
+
+	u, err := url.Parse(callback)
+	if err != nil {
+		return fmt.Errorf("invalid hub.callback: %v", err)
+	}
+
+	q := u.Query()
+	q.Set("hub.mode", mode)
+	q.Set("hub.topic", topic)
+	q.Set("hub.challenge", challenge)
+	if Mode(mode) == ModeSubscribe {
+		q.Set("hub.lease_seconds", strconv.Itoa(leaseSeconds))
+	}
+	u.RawQuery = q.Encode()
+
+	ctx, cancel := context.WithTimeout(context.Background(), verifyTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("could not build verification request: %v", err)
+	}
+
+	resp, err := verifyClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("callback unreachable: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("callback returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return fmt.Errorf("could not read callback response: %v", err)
+	}
+	if string(body) != challenge {
+		return fmt.Errorf("callback did not echo the challenge")
+	}
+
+	return nil
+}